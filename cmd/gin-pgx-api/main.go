@@ -6,23 +6,49 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rylenko/leadgen-market-task/internal/ginapi"
 	"github.com/rylenko/leadgen-market-task/internal/logic"
+	"github.com/rylenko/leadgen-market-task/internal/memory"
 	"github.com/rylenko/leadgen-market-task/internal/pgx"
+	"github.com/rylenko/leadgen-market-task/internal/sqlite"
 )
 
 const (
-	usage = "Usage: <config-path>"
+	usage = "Usage: <config-path> [import-feed <source> <feed-path> | " +
+		"migrate status|up|down|to <version>]"
 	postgresqlURIFormat = "postgresql://%s:%s@%s:%d/%s"
+
+	// Subcommand to import partner feed instead of launching API.
+	importFeedCommand = "import-feed"
+
+	// Subcommand to show, apply or revert migrations of the database schema
+	// instead of launching API, and its actions.
+	migrateCommand = "migrate"
+	migrateStatusAction = "status"
+	migrateUpAction = "up"
+	migrateDownAction = "down"
+	migrateToAction = "to"
+
+	// Names of the repository implementations which can be selected by the
+	// config. PostgreSQL is used if repository is not set.
+	memoryRepository = "memory"
+	pgxRepository = "pgx"
+	sqliteRepository = "sqlite"
 )
 
 type Config struct {
-	Host string     `json:"host"`
-	Port int        `json:"port"`
-	User string     `json:"user"`
-	Password string `json:"password"`
-	Db string       `json:"db"`
+	Repository string `json:"repository"`
+	Host string       `json:"host"`
+	Port int          `json:"port"`
+	User string       `json:"user"`
+	Password string   `json:"password"`
+	Db string         `json:"db"`
+	// Path to the database file of SQLite repository.
+	Path string       `json:"path"`
 }
 
 // Builds database URI using parsed config parameters.
@@ -36,6 +62,15 @@ func (config *Config) buildURI() string {
 		config.Db)
 }
 
+// Repositories selected by the config.
+type repositories struct {
+	building logic.BuildingRepository
+	idempotency logic.IdempotencyRepository
+
+	// Closes the repositories.
+	close func()
+}
+
 // Opens and parses config file using passed path.
 func openConfig(path string) (*Config, error) {
 	// Try to open config file.
@@ -54,12 +89,192 @@ func openConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to decode JSON config %v", err)
 	}
 
+	// Check that repository implementation is known.
+	switch config.Repository {
+	case "":
+		config.Repository = pgxRepository
+	case memoryRepository, pgxRepository:
+	case sqliteRepository:
+		if config.Path == "" {
+			return nil, fmt.Errorf(
+				"path of %s repository is not set", sqliteRepository)
+		}
+	default:
+		return nil, fmt.Errorf("unknown repository %q", config.Repository)
+	}
+
 	return &config, nil
 }
 
+// Opens buildings and idempotency keys repositories selected by the config.
+// Repositories of PostgreSQL share a single connection pool.
+func openRepositories(
+		ctx context.Context, config *Config) (*repositories, error) {
+	switch config.Repository {
+	case memoryRepository:
+		return &repositories{
+			building: memory.NewBuildingRepositoryImpl(),
+			idempotency: memory.NewIdempotencyRepositoryImpl(),
+			close: func() {},
+		}, nil
+	case sqliteRepository:
+		building, err := sqlite.OpenBuildingRepositoryImpl(ctx, config.Path)
+		if err != nil {
+			return nil, err
+		}
+		idempotency, err := sqlite.OpenIdempotencyRepositoryImpl(
+			ctx, config.Path)
+		if err != nil {
+			building.Close()
+			return nil, err
+		}
+		return &repositories{
+			building: building,
+			idempotency: idempotency,
+			close: func() {
+				idempotency.Close()
+				building.Close()
+			},
+		}, nil
+	}
+
+	// Try to open connection pool of the repositories.
+	pool, err := pgxpool.New(ctx, config.buildURI())
+	if err != nil {
+		return nil, err
+	}
+	return &repositories{
+		building: pgx.NewBuildingRepositoryImpl(pool),
+		idempotency: pgx.NewIdempotencyRepositoryImpl(pool),
+		close: pool.Close,
+	}, nil
+}
+
+// Imports feed of passed source from file with passed path using service.
+func importFeed(
+		ctx context.Context,
+		service logic.BuildingService,
+		source string,
+		path string) error {
+	// Try to open feed file.
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open feed %s: %v", path, err)
+	}
+	defer file.Close()
+
+	// Initialize service before import.
+	if err := service.Init(ctx); err != nil {
+		return fmt.Errorf("failed to initialize building service: %v", err)
+	}
+
+	// Try to import feed.
+	result, err := service.ImportFeed(ctx, source, file)
+	if err != nil {
+		return err
+	}
+
+	// Report result of the import.
+	for _, skipped := range result.Skipped {
+		log.Printf("skipped %s", skipped)
+	}
+	log.Printf(
+		"imported feed %s: %d inserted, %d updated, %d skipped",
+		path,
+		result.Inserted,
+		result.Updated,
+		len(result.Skipped))
+	return nil
+}
+
+// Checks that passed command line arguments match the usage.
+func validateArgs(args []string) bool {
+	switch {
+	case len(args) == 2:
+		return true
+	case len(args) == 5 && args[2] == importFeedCommand:
+		return true
+	case len(args) == 4 && args[2] == migrateCommand:
+		return args[3] == migrateStatusAction ||
+			args[3] == migrateUpAction ||
+			args[3] == migrateDownAction
+	case len(args) == 5 && args[2] == migrateCommand:
+		return args[3] == migrateToAction
+	default:
+		return false
+	}
+}
+
+// Runs migrate action with its arguments using migrator.
+func migrate(
+		ctx context.Context, migrator *pgx.Migrator, args []string) error {
+	switch args[0] {
+	case migrateStatusAction:
+		// Try to get statuses of the migrations and print them.
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied at " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf(
+				"%04d %s: %s\n",
+				status.Migration.Version,
+				status.Migration.Name,
+				state)
+		}
+		return nil
+	case migrateUpAction:
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		logMigrations("applied", applied)
+		return nil
+	case migrateDownAction:
+		reverted, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if reverted == nil {
+			log.Printf("no applied migrations")
+			return nil
+		}
+		logMigrations("reverted", []*pgx.Migration{reverted})
+		return nil
+	default:
+		// Try to parse target version and migrate to it.
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("version %s is not uint64 type", args[1])
+		}
+		changed, err := migrator.To(ctx, version)
+		if err != nil {
+			return err
+		}
+		logMigrations("changed", changed)
+		return nil
+	}
+}
+
+// Logs passed migrations with the action done with them.
+func logMigrations(action string, migrations []*pgx.Migration) {
+	if len(migrations) == 0 {
+		log.Printf("schema is up to date")
+		return
+	}
+	for _, migration := range migrations {
+		log.Printf(
+			"%s migration %04d %s", action, migration.Version, migration.Name)
+	}
+}
+
 func main() {
-	// Validate arguments count.
-	if len(os.Args) != 2 {
+	// Validate arguments.
+	if !validateArgs(os.Args) {
 		log.Fatal(usage)
 	}
 
@@ -69,18 +284,54 @@ func main() {
 		log.Fatalf("failed to open config file: %v", err)
 	}
 
-	// Open buildings repository.
-	repository, err := pgx.OpenBuildingRepositoryImpl(
-		context.Background(), config.buildURI())
+	// Run migrate subcommand without opening repositories, which apply pending
+	// migrations on initialization.
+	if len(os.Args) > 2 && os.Args[2] == migrateCommand {
+		if config.Repository != pgxRepository {
+			log.Fatalf(
+				"%s repository does not have migrations", config.Repository)
+		}
+
+		migrator, err := pgx.OpenMigrator(
+			context.Background(), config.buildURI())
+		if err != nil {
+			log.Fatalf("failed to open migrator: %v", err)
+		}
+		defer migrator.Close()
+
+		err = migrate(context.Background(), migrator, os.Args[3:])
+		if err != nil {
+			log.Fatalf("failed to migrate: %v", err)
+		}
+		return
+	}
+
+	// Open buildings and idempotency keys repositories.
+	repositories, err := openRepositories(context.Background(), config)
 	if err != nil {
-		log.Fatalf("failed to open building repository: %v", err)
+		log.Fatalf("failed to open repositories: %v", err)
 	}
-	defer repository.Close()
+	defer repositories.close()
 
 	// Create a new instance of building service.
-	service := logic.NewBuildingServiceImpl(repository)
+	service := logic.NewBuildingServiceImpl(repositories.building)
+
+	// Run subcommand if it is passed instead of API.
+	if len(os.Args) > 2 {
+		err := importFeed(context.Background(), service, os.Args[3], os.Args[4])
+		if err != nil {
+			log.Fatalf("failed to import feed: %v", err)
+		}
+		return
+	}
 
-	if err := ginapi.Launch(context.Background(), service, ":8000"); err != nil {
+	// Create a new instance of idempotency service.
+	idempotencyService := logic.NewIdempotencyServiceImpl(
+		repositories.idempotency)
+
+	err = ginapi.Launch(
+		context.Background(), service, idempotencyService, ":8000")
+	if err != nil {
 		log.Fatalf("failed to launch API: %v", err)
 	}
 }
@@ -5,6 +5,10 @@ package domain
 type Building struct {
 	Id int64
 	Info *BuildingInfo
+
+	// Identifier of the building in an external system, which is nil if the
+	// building is not synchronized with any.
+	ExternalId *ExternalId
 }
 
 // NewBuilding creates a new instance of building structure.
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// Minimum plausible handover year.
+	MinHandoverYear = 1800
+
+	// Maximum count of years from now within which building can be handed over.
+	MaxHandoverYearsAhead = 15
+
+	// Minimum allowed floors count.
+	MinFloorsCount = 1
+
+	// Maximum allowed floors count.
+	MaxFloorsCount = 200
+
+	// Maximum allowed length of the name in characters.
+	MaxNameLength = 200
+
+	// Maximum allowed length of the city in characters.
+	MaxCityLength = 100
+)
+
+// Names of the building information fields in validation errors.
+const (
+	NameField = "name"
+	CityField = "city"
+	HandoverYearField = "handover_year"
+	FloorsCountField = "floors_count"
+)
+
+// NormalizeBuildingInfo removes leading and trailing spaces of the name and
+// the city.
+func NormalizeBuildingInfo(info *BuildingInfo) {
+	info.Name = strings.TrimSpace(info.Name)
+	info.City = strings.TrimSpace(info.City)
+}
+
+// ValidateBuildingInfo checks all fields of building information and returns
+// ValidationError with every failed field or nil.
+func ValidateBuildingInfo(info *BuildingInfo) error {
+	var errs []*FieldError
+	for _, fieldErr := range []*FieldError{
+		ValidateBuildingName(info.Name),
+		ValidateBuildingCity(info.City),
+		ValidateBuildingHandoverYear(info.HandoverYear),
+		ValidateBuildingFloorsCount(info.FloorsCount),
+	} {
+		if fieldErr != nil {
+			errs = append(errs, fieldErr)
+		}
+	}
+
+	if len(errs) > 0 {
+		return NewValidationError(errs...)
+	}
+	return nil
+}
+
+// ValidateBuildingName checks that trimmed name is not empty and not too long.
+func ValidateBuildingName(name string) *FieldError {
+	return validateText(NameField, name, MaxNameLength)
+}
+
+// ValidateBuildingCity checks that trimmed city is not empty and not too long.
+func ValidateBuildingCity(city string) *FieldError {
+	return validateText(CityField, city, MaxCityLength)
+}
+
+// ValidateBuildingHandoverYear checks that handover year is plausible: not too
+// old and not too far in the future.
+func ValidateBuildingHandoverYear(handoverYear uint64) *FieldError {
+	maxHandoverYear := uint64(time.Now().Year() + MaxHandoverYearsAhead)
+	if handoverYear < MinHandoverYear || handoverYear > maxHandoverYear {
+		return NewFieldErrorf(
+			HandoverYearField,
+			"must be between %d and %d",
+			MinHandoverYear,
+			maxHandoverYear)
+	}
+	return nil
+}
+
+// ValidateBuildingFloorsCount checks that floors count is in allowed range.
+func ValidateBuildingFloorsCount(floorsCount uint64) *FieldError {
+	if floorsCount < MinFloorsCount || floorsCount > MaxFloorsCount {
+		return NewFieldErrorf(
+			FloorsCountField,
+			"must be between %d and %d",
+			MinFloorsCount,
+			MaxFloorsCount)
+	}
+	return nil
+}
+
+// Checks that trimmed text field is not empty and not longer than passed
+// length in characters.
+func validateText(field, text string, maxLength int) *FieldError {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return NewFieldError(field, "must not be empty")
+	}
+	if utf8.RuneCountInString(text) > maxLength {
+		return NewFieldErrorf(
+			field, "must be at most %d characters long", maxLength)
+	}
+	return nil
+}
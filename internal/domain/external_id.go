@@ -0,0 +1,16 @@
+package domain
+
+// ExternalId places identifier of a building in an external system, such as a
+// partner feed or CRM.
+type ExternalId struct {
+	Source string
+	Id string
+}
+
+// NewExternalId creates a new instance of external identifier.
+func NewExternalId(source, id string) *ExternalId {
+	return &ExternalId{
+		Source: source,
+		Id: id,
+	}
+}
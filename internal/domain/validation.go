@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes a field of the domain model that does not satisfy its
+// rules.
+type FieldError struct {
+	Field string
+	Message string
+}
+
+// NewFieldError creates a new instance of field error.
+func NewFieldError(field, message string) *FieldError {
+	return &FieldError{
+		Field: field,
+		Message: message,
+	}
+}
+
+// NewFieldErrorf creates a new instance of field error with formatted message.
+func NewFieldErrorf(field, format string, args ...any) *FieldError {
+	return NewFieldError(field, fmt.Sprintf(format, args...))
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationError describes all fields of the domain model that do not satisfy
+// their rules.
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+// NewValidationError creates a new instance of validation error using errors
+// of the failed fields.
+func NewValidationError(errs ...*FieldError) *ValidationError {
+	return &ValidationError{
+		Errors: errs,
+	}
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		messages = append(messages, fieldErr.Error())
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
@@ -0,0 +1,122 @@
+package ginapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+)
+
+// Binds JSON body of the request to passed model. If it fails, pushes a
+// problem describing the failure and returns false.
+func bindJSON(c *gin.Context, model any) bool {
+	if err := c.ShouldBindJSON(model); err != nil {
+		getBindingProblem(err, model).Push(c)
+		return false
+	}
+	return true
+}
+
+// Gets problem describing failed binding of passed model. Raw errors of the
+// decoder and the validator are converted to the errors of the fields named by
+// their JSON names.
+func getBindingProblem(err error, model any) *Problem {
+	var (
+		validationErrs validator.ValidationErrors
+		typeErr *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+	)
+
+	switch {
+	case errors.As(err, &validationErrs):
+		fieldErrs := make([]*domain.FieldError, 0, len(validationErrs))
+		for _, validationErr := range validationErrs {
+			fieldErrs = append(fieldErrs, domain.NewFieldError(
+				getJSONFieldName(model, validationErr.StructField()),
+				describeValidationRule(validationErr)))
+		}
+		return NewProblem(
+					http.StatusUnprocessableEntity,
+					"request body does not satisfy its rules").
+				WithType("validation_failed").
+				WithFieldErrors(fieldErrs)
+	case errors.As(err, &typeErr):
+		message := "must be " + describeJSONType(typeErr.Type)
+		if typeErr.Field == "" {
+			return NewProblem(http.StatusBadRequest, "request body "+message).
+				WithType("invalid_body")
+		}
+		return NewProblem(
+					http.StatusBadRequest, "request body contains field of wrong type").
+				WithType("invalid_body").
+				WithFieldErrors([]*domain.FieldError{
+					domain.NewFieldError(typeErr.Field, message),
+				})
+	case errors.As(err, &syntaxErr) ||
+			errors.Is(err, io.EOF) ||
+			errors.Is(err, io.ErrUnexpectedEOF):
+		return NewProblem(http.StatusBadRequest, "request body is not valid JSON").
+			WithType("malformed_body")
+	default:
+		return NewProblem(http.StatusBadRequest, err.Error()).
+			WithType("invalid_body")
+	}
+}
+
+// Describes failed validation rule of the field.
+func describeValidationRule(validationErr validator.FieldError) string {
+	switch validationErr.Tag() {
+	case "required":
+		return "is required"
+	default:
+		return "does not satisfy " + validationErr.Tag() + " rule"
+	}
+}
+
+// Describes passed Go type as JSON type.
+func describeJSONType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+			reflect.Uint64:
+		return "non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Array, reflect.Slice:
+		return "array"
+	case reflect.Pointer:
+		return describeJSONType(t.Elem())
+	default:
+		return "object"
+	}
+}
+
+// Gets JSON name of the struct field of passed model. Struct field name is
+// returned if it has no JSON name.
+func getJSONFieldName(model any, structField string) string {
+	modelType := reflect.TypeOf(model)
+	if modelType.Kind() == reflect.Pointer {
+		modelType = modelType.Elem()
+	}
+
+	field, ok := modelType.FieldByName(structField)
+	if !ok {
+		return structField
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return structField
+	}
+	return name
+}
@@ -0,0 +1,164 @@
+package ginapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+const (
+	// Maximum allowed count of buildings in the batch.
+	maxBatchSize = 1000
+
+	// Statuses of the batch items.
+	batchItemStatusCreated = "created"
+	batchItemStatusInvalid = "invalid"
+	batchItemStatusConflict = "conflict"
+	batchItemStatusFailed = "failed"
+	batchItemStatusAborted = "aborted"
+)
+
+// Result of creating a building of the batch JSON view to make responses.
+//
+// Status is one of "created", "invalid", "failed" or "aborted". Aborted
+// buildings are valid, but not created, because another building of the
+// atomic batch is not created. Errors of the fields are set for invalid
+// buildings in the same form as in the problem details.
+type BuildingBatchItemView struct {
+	Index int                `json:"index"`
+	Status string            `json:"status"`
+	Id *int64                `json:"id,omitempty"`
+	Error *string            `json:"error,omitempty"`
+	Errors []*FieldErrorView `json:"errors,omitempty"`
+}
+
+// Results of creating a batch of buildings JSON view to make responses.
+type BuildingBatchView struct {
+	Items []*BuildingBatchItemView `json:"items"`
+	Created int                    `json:"created"`
+	Failed int                     `json:"failed"`
+}
+
+// Adds item view with passed status and error to the batch view and returns
+// it.
+func (view *BuildingBatchView) addItem(
+		index int,
+		status string,
+		id *int64,
+		message string) *BuildingBatchItemView {
+	item := &BuildingBatchItemView{
+		Index: index,
+		Status: status,
+		Id: id,
+	}
+	if message != "" {
+		item.Error = &message
+	}
+	view.Items = append(view.Items, item)
+
+	// Count created and not created items.
+	if status == batchItemStatusCreated {
+		view.Created++
+	} else {
+		view.Failed++
+	}
+	return item
+}
+
+// Decodes and validates items of the batch according to the domain rules.
+// Returns building information of the valid items with their indexes and
+// problems of invalid items by their indexes. Problems describe items the same
+// way as the bodies of single building requests.
+func decodeBatchItems(items []json.RawMessage) (
+		infos []*domain.BuildingInfo,
+		indexes []int,
+		invalid map[int]*Problem) {
+	invalid = make(map[int]*Problem)
+
+	for i, item := range items {
+		// Try to decode an item.
+		var body BuildingBody
+		if err := json.Unmarshal(item, &body); err != nil {
+			invalid[i] = getBindingProblem(err, &body)
+			continue
+		}
+
+		// Validate an item, so each item of the atomic batch gets its own
+		// status even if the batch is not created.
+		info := body.toInfo()
+		domain.NormalizeBuildingInfo(info)
+		var validationErr *domain.ValidationError
+		err := domain.ValidateBuildingInfo(info)
+		if errors.As(err, &validationErr) {
+			invalid[i] = NewProblem(
+						http.StatusUnprocessableEntity, "invalid building").
+					WithType("validation_failed").
+					WithFieldErrors(validationErr.Errors)
+			continue
+		}
+
+		infos = append(infos, info)
+		indexes = append(indexes, i)
+	}
+
+	return infos, indexes, invalid
+}
+
+// Gets batch view using items count, problems of invalid items and results of
+// creating valid items with their indexes. If batch is atomic and there are
+// invalid items, valid ones are considered aborted.
+func getBuildingBatchView(
+		count int,
+		invalid map[int]*Problem,
+		indexes []int,
+		results []*logic.BuildingBatchResult) *BuildingBatchView {
+	view := &BuildingBatchView{
+		Items: make([]*BuildingBatchItemView, 0, count),
+	}
+
+	// Map results to the indexes of the items.
+	resultsByIndex := make(map[int]*logic.BuildingBatchResult, len(results))
+	for j, result := range results {
+		resultsByIndex[indexes[j]] = result
+	}
+
+	for i := 0; i < count; i++ {
+		// Add invalid item.
+		if problem, ok := invalid[i]; ok {
+			item := view.addItem(i, batchItemStatusInvalid, nil, problem.Detail)
+			item.Errors = problem.Errors
+			continue
+		}
+
+		// Add valid item according to its result.
+		result, ok := resultsByIndex[i]
+		switch {
+		case !ok || errors.Is(result.Err, logic.ErrBuildingBatchAborted):
+			view.addItem(
+				i, batchItemStatusAborted, nil, logic.ErrBuildingBatchAborted.Error())
+		case errors.Is(result.Err, logic.ErrConflict):
+			view.addItem(i, batchItemStatusConflict, nil, getErrorMessage(result.Err))
+		case errors.Is(result.Err, logic.ErrValidation):
+			view.addItem(i, batchItemStatusInvalid, nil, getErrorMessage(result.Err))
+		case result.Err != nil:
+			view.addItem(
+				i, batchItemStatusFailed, nil, "building is rejected by the storage")
+		default:
+			view.addItem(i, batchItemStatusCreated, &result.Building.Id, "")
+		}
+	}
+
+	return view
+}
+
+// Gets message of the logic error, which does not describe the storage.
+func getErrorMessage(err error) string {
+	var logicErr *logic.Error
+	if errors.As(err, &logicErr) {
+		return logicErr.Message
+	}
+	return err.Error()
+}
@@ -1,9 +1,13 @@
 package ginapi
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -11,6 +15,17 @@ import (
 	"github.com/rylenko/leadgen-market-task/internal/logic"
 )
 
+const (
+	// Header with reference to the next page of buildings.
+	linkHeader = "Link"
+
+	// Header with cursor of the next page of buildings.
+	nextCursorHeader = "X-Next-Cursor"
+
+	// Header with count of all filtered buildings.
+	totalCountHeader = "X-Total-Count"
+)
+
 // Controller to handle building routes.
 type BuildingController struct {
 	ctx context.Context
@@ -26,24 +41,27 @@ type BuildingController struct {
 // @Accept      json
 // @Produce     json
 // @Param       building                                  body     BuildingBody true "Create building"
+// @Param       Idempotency-Key                           header   string       false "key to identify retries of the same request"
 // @Success     201                                       {object} BuildingView
-// @Failure     400                                       {object} Error
-// @Failure     500                                       {object} Error
+// @Failure     400                                       {object} Problem
+// @Failure     409                                       {object} Problem
+// @Failure     413                                       {object} Problem
+// @Failure     422                                       {object} Problem
+// @Failure     500                                       {object} Problem
+// @Failure     503                                       {object} Problem
 // @Router      /buildings                                [post]
 func (controller *BuildingController) Create(c *gin.Context) {
 	var body BuildingBody
 
 	// Try to bind creation data to body structure.
-	if err := c.ShouldBindJSON(&body); err != nil {
-		NewError(http.StatusBadRequest, err.Error()).Push(c)
+	if !bindJSON(c, &body) {
 		return
 	}
 
 	// Use service to create a new building.
 	building, err := controller.service.Create(controller.ctx, body.toInfo())
 	if err != nil {
-		c.Error(err)
-		NewError(http.StatusInternalServerError, "internal error").Push(c)
+		pushServiceError(c, err)
 		return
 	}
 
@@ -53,47 +71,817 @@ func (controller *BuildingController) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, view)
 }
 
+// CreateBatch godoc
+//
+// @Summary     Creates a batch of buildings
+// @Description Creates buildings using passed array of data and returns status of each of them. Atomic batch is created entirely or not created at all. Otherwise, valid buildings are created even if others are not.
+// @ID          create-buildings-batch
+// @Tags        building
+// @Accept      json
+// @Produce     json
+// @Param       buildings                                 body     []BuildingBody true  "Create buildings"
+// @Param       atomic                                    query    bool           false "whether to create all buildings or none of them, true by default"
+// @Param       Idempotency-Key                           header   string         false "key to identify retries of the same request"
+// @Success     201                                       {object} BuildingBatchView "all buildings are created"
+// @Success     207                                       {object} BuildingBatchView "some buildings of non-atomic batch are not created"
+// @Failure     400                                       {object} Problem
+// @Failure     422                                       {object} BuildingBatchView "atomic batch is not created"
+// @Failure     409                                       {object} Problem
+// @Failure     413                                       {object} Problem
+// @Failure     500                                       {object} Problem
+// @Failure     503                                       {object} Problem
+// @Router      /buildings/batch                          [post]
+func (controller *BuildingController) CreateBatch(c *gin.Context) {
+	// Try to extract atomicity flag from context. Batch is atomic by default.
+	atomic := true
+	if c.Query("atomic") != "" {
+		var err error
+		if atomic, err = extractBoolQuery(c, "atomic"); err != nil {
+			NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+			return
+		}
+	}
+
+	// Try to bind batch items without decoding them, so each item is
+	// validated separately.
+	var items []json.RawMessage
+	if !bindJSON(c, &items) {
+		return
+	}
+	if len(items) == 0 || len(items) > maxBatchSize {
+		message := fmt.Sprintf(
+			"batch must contain from 1 to %d buildings", maxBatchSize)
+		NewProblem(http.StatusBadRequest, message).Push(c)
+		return
+	}
+
+	// Decode and validate batch items.
+	infos, indexes, invalid := decodeBatchItems(items)
+
+	// Use service to create valid buildings unless atomic batch contains
+	// invalid ones.
+	var results []*logic.BuildingBatchResult
+	if len(infos) > 0 && (!atomic || len(invalid) == 0) {
+		var err error
+		results, err = controller.service.CreateBatch(
+			controller.ctx, infos, atomic)
+		if err != nil {
+			pushServiceError(c, err)
+			return
+		}
+	}
+
+	// Log errors of rejected buildings.
+	for _, result := range results {
+		if result.Err != nil &&
+				!errors.Is(result.Err, logic.ErrBuildingBatchAborted) {
+			c.Error(result.Err)
+		}
+	}
+
+	// Make response with batch view and status according to the results.
+	view := getBuildingBatchView(len(items), invalid, indexes, results)
+	switch {
+	case view.Failed == 0:
+		c.JSON(http.StatusCreated, view)
+	case atomic:
+		c.JSON(http.StatusUnprocessableEntity, view)
+	default:
+		c.JSON(http.StatusMultiStatus, view)
+	}
+}
+
+// Delete godoc
+//
+// @Summary     Deletes a building
+// @Description Deletes a building by its identifier
+// @ID          delete-building
+// @Tags        building
+// @Produce     json
+// @Param       id                                        path     int    true  "building identifier"
+// @Param       Idempotency-Key                           header   string false "key to identify retries of the same request"
+// @Success     204
+// @Failure     400                                       {object} Problem
+// @Failure     404                                       {object} Problem
+// @Failure     409                                       {object} Problem
+// @Failure     413                                       {object} Problem
+// @Failure     500                                       {object} Problem
+// @Failure     503                                       {object} Problem
+// @Router      /buildings/{id}                           [delete]
+func (controller *BuildingController) Delete(c *gin.Context) {
+	// Try to extract building identifier from context.
+	id, err := extractId(c)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+
+	// Use service to delete the building.
+	if err := controller.service.Delete(controller.ctx, id); err != nil {
+		pushServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Export godoc
+//
+// @Summary     Exports buildings
+// @Description Streams all buildings according to passed filter, search and sort parameters in passed format without pagination. Buildings with equal sort keys are ordered by identifier.
+// @ID          export-buildings
+// @Tags        building
+// @Produce     text/csv
+// @Produce     application/x-ndjson
+// @Produce     application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param       format                                                   query    string       false "export format, csv by default" Enums(csv, ndjson, xlsx)
+// @Param       city                                                     query    string       false "city filter, same as city[eq]"
+// @Param       city[ne]                                                 query    string       false "city inequality filter"
+// @Param       city[in]                                                 query    string       false "comma-separated cities to include"
+// @Param       city[nin]                                                query    string       false "comma-separated cities to exclude"
+// @Param       handover_year                                            query    int          false "handover year filter, same as handover_year[eq]"
+// @Param       handover_year[ne]                                        query    int          false "handover year inequality filter"
+// @Param       handover_year[gt]                                        query    int          false "minimum handover year, exclusive"
+// @Param       handover_year[gte]                                       query    int          false "minimum handover year, inclusive"
+// @Param       handover_year[lt]                                        query    int          false "maximum handover year, exclusive"
+// @Param       handover_year[lte]                                       query    int          false "maximum handover year, inclusive"
+// @Param       handover_year[in]                                        query    string       false "comma-separated handover years to include"
+// @Param       handover_year[nin]                                       query    string       false "comma-separated handover years to exclude"
+// @Param       floors_count                                             query    int          false "floors count filter, same as floors_count[eq]"
+// @Param       floors_count[ne]                                         query    int          false "floors count inequality filter"
+// @Param       floors_count[gt]                                         query    int          false "minimum floors count, exclusive"
+// @Param       floors_count[gte]                                        query    int          false "minimum floors count, inclusive"
+// @Param       floors_count[lt]                                         query    int          false "maximum floors count, exclusive"
+// @Param       floors_count[lte]                                        query    int          false "maximum floors count, inclusive"
+// @Param       floors_count[in]                                         query    string       false "comma-separated floors counts to include"
+// @Param       floors_count[nin]                                        query    string       false "comma-separated floors counts to exclude"
+// @Param       q                                                        query    string       false "text search over names, tolerant to typos and partial names"
+// @Param       search_city                                              query    bool         false "whether to search over cities too"
+// @Param       sort                                                     query    string       false "comma-separated fields to sort by, descending if prefixed with '-', e.g. -handover_year,name"
+// @Success     200                                                      {file}   file
+// @Failure     400                                                      {object} Problem
+// @Failure     500                                                      {object} Problem
+// @Failure     503                                                      {object} Problem
+// @Router      /buildings/export                                        [get]
+func (controller *BuildingController) Export(c *gin.Context) {
+	// Try to extract export format from context.
+	format, err := parseExportFormat(c.Query("format"))
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+
+	// Try to extract filters and sort keys from context.
+	filters, err := extractSortedFilters(c)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+
+	// Create encoder of the format. Response is buffered, so nothing is sent
+	// until the buffer is full.
+	writer := bufio.NewWriter(c.Writer)
+	encoder, err := newBuildingEncoder(format, writer)
+	if err != nil {
+		c.Error(err)
+		NewProblem(http.StatusInternalServerError, "internal error").Push(c)
+		return
+	}
+	c.Header("Content-Type", exportContentTypes[format])
+	c.Header(
+		"Content-Disposition",
+		fmt.Sprintf(`attachment; filename="buildings.%s"`, format))
+	c.Status(http.StatusOK)
+
+	// Use service to encode each building.
+	err = controller.service.GetEach(controller.ctx, filters, encoder.Encode)
+	if err == nil {
+		if err = encoder.Close(); err == nil {
+			err = writer.Flush()
+		}
+	}
+	if err != nil {
+		// Response can not be changed if part of the export is already sent.
+		if c.Writer.Written() {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		pushServiceError(c, err)
+	}
+}
+
+// FindDuplicates godoc
+//
+// @Summary     Finds likely duplicate buildings
+// @Description Finds pairs of buildings of the same city and handover year with similar names and close floors counts. Names are compared ignoring case, punctuation, words order and words like "ЖК". The older building of a pair is suggested as canonical.
+// @ID          find-building-duplicates
+// @Tags        building
+// @Produce     json
+// @Param       city                                      query    string false "city filter, also city[<op>] as in getall-buildings"
+// @Param       handover_year                             query    int    false "handover year filter, also handover_year[<op>] as in getall-buildings"
+// @Param       floors_count                              query    int    false "floors count filter, also floors_count[<op>] as in getall-buildings"
+// @Param       min_similarity                            query    number false "minimum similarity of names from 0 to 1, 0.8 by default"
+// @Param       max_floors_difference                     query    int    false "maximum difference of floors counts, 2 by default"
+// @Param       limit                                     query    int    false "maximum count of pairs, 100 by default, 1000 at most"
+// @Success     200                                       {array}  BuildingDuplicateView
+// @Failure     400                                       {object} Problem
+// @Failure     500                                       {object} Problem
+// @Failure     503                                       {object} Problem
+// @Router      /buildings/duplicates                     [get]
+func (controller *BuildingController) FindDuplicates(c *gin.Context) {
+	// Try to extract filters, criteria and limit from context.
+	filters, err := extractFilters(c)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+	criteria, err := extractDuplicateCriteria(c)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+	limit, err := extractLimit(c, defaultDuplicatesLimit, maxDuplicatesLimit)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+
+	// Use service to find duplicates.
+	duplicates, err := controller.service.FindDuplicates(
+		controller.ctx, filters, criteria, limit)
+	if err != nil {
+		pushServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getBuildingDuplicateViews(duplicates))
+}
+
+// Get godoc
+//
+// @Summary     Gets a building
+// @Description Gets a building by its identifier
+// @ID          get-building
+// @Tags        building
+// @Produce     json
+// @Param       id                                        path     int  true "building identifier"
+// @Success     200                                       {object} BuildingView
+// @Failure     400                                       {object} Problem
+// @Failure     404                                       {object} Problem
+// @Failure     500                                       {object} Problem
+// @Failure     503                                       {object} Problem
+// @Router      /buildings/{id}                           [get]
+func (controller *BuildingController) Get(c *gin.Context) {
+	// Try to extract building identifier from context.
+	id, err := extractId(c)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+
+	// Use service to get the building.
+	building, err := controller.service.Get(controller.ctx, id)
+	if err != nil {
+		pushServiceError(c, err)
+		return
+	}
+
+	// Make response with building view.
+	c.JSON(http.StatusOK, getBuildingView(building))
+}
+
 // GetAll godoc
 //
 // @Summary     Gets all buildings
-// @Description Gets all buildings according to passed filter paramters
+// @Description Gets a page of buildings according to passed filter, search, sort and pagination paramters. Buildings with equal sort keys are ordered by identifier. Search results without sort keys are ordered by relevance. Cursor of the next page is returned in headers.
 // @ID          getall-buildings
 // @Tags        building
 // @Accept      json
 // @Produce     json
-// @Param       city                                                     query    string       false "city filter"
-// @Param       handover_year                                            query    int          false "handover year filter"
-// @Param       floors_count                                             query    int          false "floors count filter"
+// @Param       city                                                     query    string       false "city filter, same as city[eq]"
+// @Param       city[ne]                                                 query    string       false "city inequality filter"
+// @Param       city[in]                                                 query    string       false "comma-separated cities to include"
+// @Param       city[nin]                                                query    string       false "comma-separated cities to exclude"
+// @Param       handover_year                                            query    int          false "handover year filter, same as handover_year[eq]"
+// @Param       handover_year[ne]                                        query    int          false "handover year inequality filter"
+// @Param       handover_year[gt]                                        query    int          false "minimum handover year, exclusive"
+// @Param       handover_year[gte]                                       query    int          false "minimum handover year, inclusive"
+// @Param       handover_year[lt]                                        query    int          false "maximum handover year, exclusive"
+// @Param       handover_year[lte]                                       query    int          false "maximum handover year, inclusive"
+// @Param       handover_year[in]                                        query    string       false "comma-separated handover years to include"
+// @Param       handover_year[nin]                                       query    string       false "comma-separated handover years to exclude"
+// @Param       floors_count                                             query    int          false "floors count filter, same as floors_count[eq]"
+// @Param       floors_count[ne]                                         query    int          false "floors count inequality filter"
+// @Param       floors_count[gt]                                         query    int          false "minimum floors count, exclusive"
+// @Param       floors_count[gte]                                        query    int          false "minimum floors count, inclusive"
+// @Param       floors_count[lt]                                         query    int          false "maximum floors count, exclusive"
+// @Param       floors_count[lte]                                        query    int          false "maximum floors count, inclusive"
+// @Param       floors_count[in]                                         query    string       false "comma-separated floors counts to include"
+// @Param       floors_count[nin]                                        query    string       false "comma-separated floors counts to exclude"
+// @Param       q                                                        query    string       false "text search over names, tolerant to typos and partial names"
+// @Param       search_city                                              query    bool         false "whether to search over cities too"
+// @Param       sort                                                     query    string       false "comma-separated fields to sort by, descending if prefixed with '-', e.g. -handover_year,name"
+// @Param       limit                                                    query    int          false "maximum page size, 100 by default, 1000 at most"
+// @Param       offset                                                   query    int          false "count of buildings to skip"
+// @Param       after                                                    query    string       false "cursor of the previous page to get the next one"
+// @Param       with_total                                               query    bool         false "whether to count all filtered buildings"
 // @Success     200                                                      {array}  BuildingView
-// @Failure     400                                                      {object} Error
-// @Failure     500                                                      {object} Error
+// @Header      200                                                      {string} X-Next-Cursor "cursor of the next page if the page is full"
+// @Header      200                                                      {string} Link          "reference to the next page with rel=next if the page is full"
+// @Header      200                                                      {int}    X-Total-Count "count of all filtered buildings if with_total is set"
+// @Failure     400                                                      {object} Problem
+// @Failure     500                                                      {object} Problem
+// @Failure     503                                                      {object} Problem
 // @Router      /buildings                                               [get]
 func (controller *BuildingController) GetAll(c *gin.Context) {
+	// Try to get page of buildings.
+	view, ok := controller.getPage(c, nil)
+	if !ok {
+		return
+	}
+
+	// Pass cursor of the next page in headers, so the list stays an array.
+	if view.NextCursor != nil {
+		c.Header(nextCursorHeader, *view.NextCursor)
+		c.Header(linkHeader, getNextPageLink(c, *view.NextCursor))
+	}
+
+	// Make response with building views.
+	c.JSON(http.StatusOK, view.Items)
+}
+
+// GetPage godoc
+//
+// @Summary     Gets a page of buildings
+// @Description Gets a page of buildings like getall-buildings, but in an envelope with cursor of the next page and requested facets.
+// @ID          getpage-buildings
+// @Tags        building
+// @Accept      json
+// @Produce     json
+// @Param       city                                                     query    string       false "city filter, same as city[eq]"
+// @Param       city[ne]                                                 query    string       false "city inequality filter"
+// @Param       city[in]                                                 query    string       false "comma-separated cities to include"
+// @Param       city[nin]                                                query    string       false "comma-separated cities to exclude"
+// @Param       handover_year                                            query    int          false "handover year filter, same as handover_year[eq]"
+// @Param       handover_year[ne]                                        query    int          false "handover year inequality filter"
+// @Param       handover_year[gt]                                        query    int          false "minimum handover year, exclusive"
+// @Param       handover_year[gte]                                       query    int          false "minimum handover year, inclusive"
+// @Param       handover_year[lt]                                        query    int          false "maximum handover year, exclusive"
+// @Param       handover_year[lte]                                       query    int          false "maximum handover year, inclusive"
+// @Param       handover_year[in]                                        query    string       false "comma-separated handover years to include"
+// @Param       handover_year[nin]                                       query    string       false "comma-separated handover years to exclude"
+// @Param       floors_count                                             query    int          false "floors count filter, same as floors_count[eq]"
+// @Param       floors_count[ne]                                         query    int          false "floors count inequality filter"
+// @Param       floors_count[gt]                                         query    int          false "minimum floors count, exclusive"
+// @Param       floors_count[gte]                                        query    int          false "minimum floors count, inclusive"
+// @Param       floors_count[lt]                                         query    int          false "maximum floors count, exclusive"
+// @Param       floors_count[lte]                                        query    int          false "maximum floors count, inclusive"
+// @Param       floors_count[in]                                         query    string       false "comma-separated floors counts to include"
+// @Param       floors_count[nin]                                        query    string       false "comma-separated floors counts to exclude"
+// @Param       q                                                        query    string       false "text search over names, tolerant to typos and partial names"
+// @Param       search_city                                              query    bool         false "whether to search over cities too"
+// @Param       sort                                                     query    string       false "comma-separated fields to sort by, descending if prefixed with '-', e.g. -handover_year,name"
+// @Param       limit                                                    query    int          false "maximum page size, 100 by default, 1000 at most"
+// @Param       offset                                                   query    int          false "count of buildings to skip"
+// @Param       after                                                    query    string       false "cursor of the previous page to get the next one"
+// @Param       facets                                                   query    string       false "comma-separated fields to count buildings by their values, e.g. city,handover_year"
+// @Param       with_total                                               query    bool         false "whether to count all filtered buildings"
+// @Success     200                                                      {object} BuildingPageView
+// @Header      200                                                      {int}    X-Total-Count "count of all filtered buildings if with_total is set"
+// @Failure     400                                                      {object} Problem
+// @Failure     500                                                      {object} Problem
+// @Failure     503                                                      {object} Problem
+// @Router      /buildings/page                                          [get]
+func (controller *BuildingController) GetPage(c *gin.Context) {
+	// Try to extract requested facets from context.
+	facetFields, err := extractFacetFields(c)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+
+	// Try to get page of buildings with facets.
+	view, ok := controller.getPage(c, facetFields)
+	if !ok {
+		return
+	}
+
+	// Make response with building page view.
+	c.JSON(http.StatusOK, view)
+}
+
+// GetStats godoc
+//
+// @Summary     Gets building statistics
+// @Description Gets counts of buildings grouped by city and by handover year and floors count statistics according to passed filter paramters
+// @ID          getstats-buildings
+// @Tags        building
+// @Produce     json
+// @Param       city                                                     query    string       false "city filter, same as city[eq]"
+// @Param       city[ne]                                                 query    string       false "city inequality filter"
+// @Param       city[in]                                                 query    string       false "comma-separated cities to include"
+// @Param       city[nin]                                                query    string       false "comma-separated cities to exclude"
+// @Param       handover_year                                            query    int          false "handover year filter, same as handover_year[eq]"
+// @Param       handover_year[ne]                                        query    int          false "handover year inequality filter"
+// @Param       handover_year[gt]                                        query    int          false "minimum handover year, exclusive"
+// @Param       handover_year[gte]                                       query    int          false "minimum handover year, inclusive"
+// @Param       handover_year[lt]                                        query    int          false "maximum handover year, exclusive"
+// @Param       handover_year[lte]                                       query    int          false "maximum handover year, inclusive"
+// @Param       handover_year[in]                                        query    string       false "comma-separated handover years to include"
+// @Param       handover_year[nin]                                       query    string       false "comma-separated handover years to exclude"
+// @Param       floors_count                                             query    int          false "floors count filter, same as floors_count[eq]"
+// @Param       floors_count[ne]                                         query    int          false "floors count inequality filter"
+// @Param       floors_count[gt]                                         query    int          false "minimum floors count, exclusive"
+// @Param       floors_count[gte]                                        query    int          false "minimum floors count, inclusive"
+// @Param       floors_count[lt]                                         query    int          false "maximum floors count, exclusive"
+// @Param       floors_count[lte]                                        query    int          false "maximum floors count, inclusive"
+// @Param       floors_count[in]                                         query    string       false "comma-separated floors counts to include"
+// @Param       floors_count[nin]                                        query    string       false "comma-separated floors counts to exclude"
+// @Param       q                                                        query    string       false "text search over names, tolerant to typos and partial names"
+// @Param       search_city                                              query    bool         false "whether to search over cities too"
+// @Success     200                                                      {object} BuildingStatsView
+// @Failure     400                                                      {object} Problem
+// @Failure     500                                                      {object} Problem
+// @Failure     503                                                      {object} Problem
+// @Router      /buildings/stats                                         [get]
+func (controller *BuildingController) GetStats(c *gin.Context) {
 	// Try to extract building filters from context.
 	filters, err := extractFilters(c)
 	if err != nil {
-		NewError(http.StatusBadRequest, err.Error()).Push(c)
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
 		return
 	}
 
-	// Try to get all buildings.
-	buildings, err := controller.service.GetAll(controller.ctx, filters)
+	// Try to get building statistics.
+	stats, err := controller.service.GetStats(controller.ctx, filters)
 	if err != nil {
-		c.Error(err)
-		NewError(http.StatusInternalServerError, "internal error").Push(c)
+		pushServiceError(c, err)
 		return
 	}
 
-	// Convert building models to JSON view.
-	var views []*BuildingView
-	for _, building := range buildings {
-		views = append(views, getBuildingView(building))
+	// Make response with statistics view.
+	c.JSON(http.StatusOK, getBuildingStatsView(stats))
+}
+
+// Import godoc
+//
+// @Summary     Imports buildings from CSV
+// @Description Imports buildings from CSV with header. Columns are named by building fields unless other names are mapped. Invalid lines are skipped and reported. Dry run only validates lines without creating buildings.
+// @ID          import-buildings
+// @Tags        building
+// @Accept      text/csv
+// @Produce     json
+// @Param       buildings                                 body     string true  "CSV with buildings"
+// @Param       dry_run                                   query    bool   false "whether to only validate lines"
+// @Param       delimiter                                 query    string false "fields delimiter, comma by default"
+// @Param       column[name]                              query    string false "column of the name"
+// @Param       column[city]                              query    string false "column of the city"
+// @Param       column[handover_year]                     query    string false "column of the handover year"
+// @Param       column[floors_count]                      query    string false "column of the floors count"
+// @Param       Idempotency-Key                           header   string false "key to identify retries of the same request"
+// @Success     200                                       {object} BuildingImportView "dry run"
+// @Success     201                                       {object} BuildingImportView
+// @Failure     400                                       {object} Problem
+// @Failure     415                                       {object} Problem
+// @Failure     409                                       {object} Problem
+// @Failure     413                                       {object} Problem
+// @Failure     500                                       {object} Problem
+// @Failure     503                                       {object} Problem
+// @Router      /buildings/import                         [post]
+func (controller *BuildingController) Import(c *gin.Context) {
+	if c.ContentType() != csvContentType {
+		message := fmt.Sprintf("content type must be %s", csvContentType)
+		NewProblem(http.StatusUnsupportedMediaType, message).Push(c)
+		return
 	}
 
-	// Make response with building views.
+	// Try to extract dry run flag from context.
+	dryRun, err := extractBoolQuery(c, "dry_run")
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+
+	// Try to create CSV source of the buildings.
+	view := &BuildingImportView{
+		DryRun: dryRun,
+		Errors: []*BuildingImportErrorView{},
+	}
+	source, err := newCSVBuildingSource(c, c.Request.Body, view)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+
+	// Only validate lines in dry run. Otherwise, use service to import valid
+	// buildings.
+	if dryRun {
+		for source.Next() {
+		}
+	} else {
+		view.Imported, err = controller.service.Import(controller.ctx, source)
+		if err != nil && source.Err() == nil {
+			pushServiceError(c, err)
+			return
+		}
+	}
+	if err := source.Err(); err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+
+	// Make response with import report.
+	if dryRun {
+		c.JSON(http.StatusOK, view)
+	} else {
+		c.JSON(http.StatusCreated, view)
+	}
+}
+
+// Merge godoc
+//
+// @Summary     Merges a duplicate into a building
+// @Description Deletes a duplicate building and keeps the building with passed identifier as canonical. External identifier of the duplicate is moved to the building if the latter does not have one.
+// @ID          merge-building
+// @Tags        building
+// @Accept      json
+// @Produce     json
+// @Param       id                                        path     int               true "canonical building identifier"
+// @Param       merge                                     body     BuildingMergeBody true "Merge duplicate"
+// @Param       Idempotency-Key                           header   string            false "key to identify retries of the same request"
+// @Success     200                                       {object} BuildingView
+// @Failure     400                                       {object} Problem
+// @Failure     404                                       {object} Problem
+// @Failure     409                                       {object} Problem
+// @Failure     413                                       {object} Problem
+// @Failure     500                                       {object} Problem
+// @Failure     503                                       {object} Problem
+// @Router      /buildings/{id}/merge                     [post]
+func (controller *BuildingController) Merge(c *gin.Context) {
+	// Try to extract building identifier from context.
+	id, err := extractId(c)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+
+	// Try to bind merge data to body structure.
+	var body BuildingMergeBody
+	if !bindJSON(c, &body) {
+		return
+	}
+
+	// Use service to merge duplicate into the building.
+	building, err := controller.service.Merge(
+		controller.ctx, id, body.DuplicateId)
+	if err != nil {
+		pushServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getBuildingView(building))
+}
+
+// Patch godoc
+//
+// @Summary     Partially updates a building
+// @Description Changes only passed fields of a building according to JSON Merge Patch (RFC 7386)
+// @ID          patch-building
+// @Tags        building
+// @Accept      application/merge-patch+json
+// @Accept      json
+// @Produce     json
+// @Param       id                                        path     int               true "building identifier"
+// @Param       patch                                     body     BuildingPatchBody true "Patch building"
+// @Param       Idempotency-Key                           header   string            false "key to identify retries of the same request"
+// @Success     200                                       {object} BuildingView
+// @Failure     400                                       {object} Problem
+// @Failure     404                                       {object} Problem
+// @Failure     409                                       {object} Problem
+// @Failure     413                                       {object} Problem
+// @Failure     422                                       {object} Problem
+// @Failure     500                                       {object} Problem
+// @Failure     503                                       {object} Problem
+// @Router      /buildings/{id}                           [patch]
+func (controller *BuildingController) Patch(c *gin.Context) {
+	// Try to extract building identifier from context.
+	id, err := extractId(c)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+
+	// Try to bind merge patch document to body structure.
+	var body BuildingPatchBody
+	if !bindJSON(c, &body) {
+		return
+	}
+
+	// Use service to patch the building.
+	building, err := controller.service.Patch(
+		controller.ctx, id, body.toPatch())
+	if err != nil {
+		pushServiceError(c, err)
+		return
+	}
+
+	// Make response with patched building view.
+	c.JSON(http.StatusOK, getBuildingView(building))
+}
+
+// Suggest godoc
+//
+// @Summary     Suggests building field values
+// @Description Gets the most frequent distinct values of building field that start with passed prefix, ignoring case
+// @ID          suggest-buildings
+// @Tags        building
+// @Produce     json
+// @Param       field                                     query    string true  "field to suggest values of" Enums(name, city)
+// @Param       prefix                                    query    string false "prefix of the values"
+// @Param       limit                                     query    int    false "maximum count of values, 10 by default, 50 at most"
+// @Success     200                                       {array}  BuildingSuggestionView
+// @Failure     400                                       {object} Problem
+// @Failure     500                                       {object} Problem
+// @Failure     503                                       {object} Problem
+// @Router      /buildings/suggest                        [get]
+func (controller *BuildingController) Suggest(c *gin.Context) {
+	// Try to extract suggestion parameters from context.
+	field, err := extractSuggestField(c)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+	limit, err := extractLimit(c, defaultSuggestionsLimit, maxSuggestionsLimit)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+
+	// Use service to get suggestions.
+	suggestions, err := controller.service.Suggest(
+		controller.ctx, field, c.Query("prefix"), limit)
+	if err != nil {
+		pushServiceError(c, err)
+		return
+	}
+
+	// Convert suggestions to JSON view.
+	views := make([]*BuildingSuggestionView, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		views = append(views, getBuildingSuggestionView(suggestion))
+	}
+
+	// Make response with suggestion views.
 	c.JSON(http.StatusOK, views)
 }
 
+// Update godoc
+//
+// @Summary     Updates a building
+// @Description Replaces all information of a building with passed data
+// @ID          update-building
+// @Tags        building
+// @Accept      json
+// @Produce     json
+// @Param       id                                        path     int          true "building identifier"
+// @Param       building                                  body     BuildingBody true "Update building"
+// @Param       Idempotency-Key                           header   string       false "key to identify retries of the same request"
+// @Success     200                                       {object} BuildingView
+// @Failure     400                                       {object} Problem
+// @Failure     404                                       {object} Problem
+// @Failure     409                                       {object} Problem
+// @Failure     413                                       {object} Problem
+// @Failure     422                                       {object} Problem
+// @Failure     500                                       {object} Problem
+// @Failure     503                                       {object} Problem
+// @Router      /buildings/{id}                           [put]
+func (controller *BuildingController) Update(c *gin.Context) {
+	// Try to extract building identifier from context.
+	id, err := extractId(c)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return
+	}
+
+	// Try to bind update data to body structure.
+	var body BuildingBody
+	if !bindJSON(c, &body) {
+		return
+	}
+
+	// Use service to update the building.
+	building, err := controller.service.Update(
+		controller.ctx, id, body.toInfo())
+	if err != nil {
+		pushServiceError(c, err)
+		return
+	}
+
+	// Make response with updated building view.
+	c.JSON(http.StatusOK, getBuildingView(building))
+}
+
+// Upsert godoc
+//
+// @Summary     Creates or updates a building by its external identifier
+// @Description Creates a building with passed identifier in passed external system or replaces information of the existing one, so repeated synchronization does not duplicate buildings
+// @ID          upsert-building
+// @Tags        building
+// @Accept      json
+// @Produce     json
+// @Param       source                                    path     string       true "name of the external system"
+// @Param       id                                        path     string       true "building identifier in the external system"
+// @Param       building                                  body     BuildingBody true "Create or update building"
+// @Param       Idempotency-Key                           header   string       false "key to identify retries of the same request"
+// @Success     200                                       {object} BuildingView "building is updated"
+// @Success     201                                       {object} BuildingView "building is created"
+// @Failure     400                                       {object} Problem
+// @Failure     409                                       {object} Problem
+// @Failure     413                                       {object} Problem
+// @Failure     422                                       {object} Problem
+// @Failure     500                                       {object} Problem
+// @Failure     503                                       {object} Problem
+// @Router      /buildings/by-external/{source}/{id}      [put]
+func (controller *BuildingController) Upsert(c *gin.Context) {
+	var body BuildingBody
+
+	// Try to bind upsert data to body structure.
+	if !bindJSON(c, &body) {
+		return
+	}
+
+	// Use service to create or update the building.
+	externalId := domain.NewExternalId(c.Param("source"), c.Param("id"))
+	building, created, err := controller.service.Upsert(
+		controller.ctx, logic.NewExternalBuildingInfo(externalId, body.toInfo()))
+	if err != nil {
+		pushServiceError(c, err)
+		return
+	}
+
+	// Make response with building view and status according to creation.
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, getBuildingView(building))
+}
+
+// Gets page of buildings according to parameters of the context with facets
+// of passed fields and sets total count header if requested. Pushes problem
+// and returns false if page can not be got.
+func (controller *BuildingController) getPage(
+		c *gin.Context,
+		facetFields []logic.BuildingField) (*BuildingPageView, bool) {
+	// Try to extract building filters from context.
+	filters, err := extractPageFilters(c)
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return nil, false
+	}
+
+	// Try to extract total count flag from context.
+	withTotal, err := extractBoolQuery(c, "with_total")
+	if err != nil {
+		NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+		return nil, false
+	}
+
+	// Try to get all buildings.
+	buildings, err := controller.service.GetAll(controller.ctx, filters)
+	if err != nil {
+		pushServiceError(c, err)
+		return nil, false
+	}
+
+	// Try to get facets if requested.
+	view := getBuildingPageView(buildings, filters)
+	if len(facetFields) > 0 {
+		facets, err := controller.service.GetFacets(
+			controller.ctx, filters, facetFields)
+		if err != nil {
+			pushServiceError(c, err)
+			return nil, false
+		}
+		view.Facets = getBuildingFacetsView(facets)
+	}
+
+	// Try to count all filtered buildings if requested.
+	if withTotal {
+		count, err := controller.service.Count(controller.ctx, filters)
+		if err != nil {
+			pushServiceError(c, err)
+			return nil, false
+		}
+		c.Header(totalCountHeader, strconv.FormatUint(count, 10))
+	}
+
+	return view, true
+}
+
 // Creates a new building controller.
 func NewBuildingController(
 		ctx context.Context, service logic.BuildingService) *BuildingController {
@@ -105,10 +893,10 @@ func NewBuildingController(
 
 // Input JSON model to create or update buildings.
 type BuildingBody struct {
-	Name string         `json:"name" binding:"required"`
-	City string         `json:"city" binding:"required"`
-	HandoverYear uint64 `json:"handover_year" binding:"required"`
-	FloorsCount uint64  `json:"floors_count" binding:"required"`
+	Name string         `json:"name"`
+	City string         `json:"city"`
+	HandoverYear uint64 `json:"handover_year"`
+	FloorsCount uint64  `json:"floors_count"`
 }
 
 // Converts JSON input to building information domain model.
@@ -117,69 +905,313 @@ func (input *BuildingBody) toInfo() *domain.BuildingInfo {
 		input.Name, input.City, input.HandoverYear, input.FloorsCount)
 }
 
+// Input JSON Merge Patch (RFC 7386) model to partially update buildings.
+//
+// Absent members are left unchanged. Building fields can not be removed, so
+// null members are rejected.
+type BuildingPatchBody struct {
+	Name *string         `json:"name"`
+	City *string         `json:"city"`
+	HandoverYear *uint64 `json:"handover_year"`
+	FloorsCount *uint64  `json:"floors_count"`
+}
+
+// Decodes merge patch document, rejecting null and unknown members.
+func (body *BuildingPatchBody) UnmarshalJSON(data []byte) error {
+	// Try to decode document members without decoding their values.
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return fmt.Errorf("merge patch must be a JSON object: %v", err)
+	}
+	if members == nil {
+		return errors.New("merge patch must be a JSON object")
+	}
+
+	// Decode every member to the corresponding field.
+	for name, value := range members {
+		var target any
+		switch name {
+		case "name":
+			target = &body.Name
+		case "city":
+			target = &body.City
+		case "handover_year":
+			target = &body.HandoverYear
+		case "floors_count":
+			target = &body.FloorsCount
+		default:
+			return fmt.Errorf("unknown field %s", name)
+		}
+
+		// Fields are required, so they can not be removed.
+		if string(value) == "null" {
+			return fmt.Errorf("field %s can not be removed", name)
+		}
+
+		// Try to decode member value.
+		if err := json.Unmarshal(value, target); err != nil {
+			return fmt.Errorf("invalid value of field %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Converts JSON merge patch to building patch logic model.
+func (body *BuildingPatchBody) toPatch() *logic.BuildingPatch {
+	return logic.NewBuildingPatch(
+		body.Name, body.City, body.HandoverYear, body.FloorsCount)
+}
+
 // Building JSON view to make responses.
 type BuildingView struct {
-	Id int64            `json:"id"`
-	Name string         `json:"name"`
-	City string         `json:"city"`
+	Id int64              `json:"id"`
+	Name string           `json:"name"`
+	City string           `json:"city"`
+	HandoverYear uint64   `json:"handover_year"`
+	FloorsCount uint64    `json:"floors_count"`
+	ExternalSource string `json:"external_source,omitempty"`
+	ExternalId string     `json:"external_id,omitempty"`
+}
+
+// Page of buildings JSON view to make responses.
+//
+// Next cursor is null if there are no more buildings. Facets are present only
+// if they are requested.
+type BuildingPageView struct {
+	Items []*BuildingView     `json:"items"`
+	NextCursor *string        `json:"next_cursor"`
+	Facets BuildingFacetsView `json:"facets,omitempty"`
+}
+
+// Building facets JSON view to make responses. It maps requested field names
+// to the counts of buildings by their values. Each facet ignores the filter on
+// its own field.
+type BuildingFacetsView map[string][]*FacetValueView
+
+// Count of buildings with the field value JSON view.
+type FacetValueView struct {
+	Value any    `json:"value"`
+	Count uint64 `json:"count"`
+}
+
+// Gets building facets view from facets logic model.
+func getBuildingFacetsView(facets *logic.BuildingFacets) BuildingFacetsView {
+	view := make(BuildingFacetsView)
+	if facets.City != nil {
+		view[string(logic.BuildingFieldCity)] = getFacetValueViews(facets.City)
+	}
+	if facets.HandoverYear != nil {
+		view[string(logic.BuildingFieldHandoverYear)] = getFacetValueViews(
+			facets.HandoverYear)
+	}
+	if facets.FloorsCount != nil {
+		view[string(logic.BuildingFieldFloorsCount)] = getFacetValueViews(
+			facets.FloorsCount)
+	}
+	return view
+}
+
+// Gets facet value views from value counts.
+func getFacetValueViews[T logic.FilterValue](
+		counts []*logic.ValueCount[T]) []*FacetValueView {
+	views := make([]*FacetValueView, 0, len(counts))
+	for _, count := range counts {
+		views = append(views, &FacetValueView{
+			Value: count.Value,
+			Count: count.Count,
+		})
+	}
+	return views
+}
+
+// Gets building page view from building domain models, selected with passed
+// filters.
+func getBuildingPageView(
+		buildings []*domain.Building,
+		filters *logic.BuildingFilters) *BuildingPageView {
+	// Convert building models to JSON view.
+	views := make([]*BuildingView, 0, len(buildings))
+	for _, building := range buildings {
+		views = append(views, getBuildingView(building))
+	}
+
+	// Full page means that there may be more buildings after the last one.
+	var nextCursor *string
+	count := uint64(len(buildings))
+	if count > 0 && filters.Limit != nil && count == *filters.Limit {
+		cursor := logic.NewBuildingCursor(buildings[count - 1]).Encode()
+		nextCursor = &cursor
+	}
+
+	return &BuildingPageView{
+		Items: views,
+		NextCursor: nextCursor,
+	}
+}
+
+// Gets Link header value with reference to the page of the current request,
+// which goes after passed cursor.
+func getNextPageLink(c *gin.Context, cursor string) string {
+	next := *c.Request.URL
+	query := next.Query()
+	query.Del("offset")
+	query.Set("after", cursor)
+	next.RawQuery = query.Encode()
+	return fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI())
+}
+
+// Building statistics JSON view to make responses.
+type BuildingStatsView struct {
+	Count uint64                            `json:"count"`
+	ByCity []*CityCountView                 `json:"by_city"`
+	ByHandoverYear []*HandoverYearCountView `json:"by_handover_year"`
+	FloorsCount *FloorsCountStatsView       `json:"floors_count"`
+}
+
+// Count of buildings in the city JSON view.
+type CityCountView struct {
+	City string  `json:"city"`
+	Count uint64 `json:"count"`
+}
+
+// Count of buildings with the handover year JSON view.
+type HandoverYearCountView struct {
 	HandoverYear uint64 `json:"handover_year"`
-	FloorsCount uint64  `json:"floors_count"`
+	Count uint64        `json:"count"`
+}
+
+// Floors count statistics JSON view. Values are null if there are no
+// buildings.
+type FloorsCountStatsView struct {
+	Min *uint64  `json:"min"`
+	Max *uint64  `json:"max"`
+	Avg *float64 `json:"avg"`
+}
+
+// Gets building statistics view from statistics logic model.
+func getBuildingStatsView(stats *logic.BuildingStats) *BuildingStatsView {
+	// Convert city counts to JSON view.
+	byCity := make([]*CityCountView, 0, len(stats.CityCounts))
+	for _, count := range stats.CityCounts {
+		byCity = append(byCity, &CityCountView{
+			City: count.Value,
+			Count: count.Count,
+		})
+	}
+
+	// Convert handover year counts to JSON view.
+	byHandoverYear := make(
+		[]*HandoverYearCountView, 0, len(stats.HandoverYearCounts))
+	for _, count := range stats.HandoverYearCounts {
+		byHandoverYear = append(byHandoverYear, &HandoverYearCountView{
+			HandoverYear: count.Value,
+			Count: count.Count,
+		})
+	}
+
+	return &BuildingStatsView{
+		Count: stats.Count,
+		ByCity: byCity,
+		ByHandoverYear: byHandoverYear,
+		FloorsCount: &FloorsCountStatsView{
+			Min: stats.MinFloorsCount,
+			Max: stats.MaxFloorsCount,
+			Avg: stats.AvgFloorsCount,
+		},
+	}
+}
+
+// Building field value suggestion JSON view to make responses.
+type BuildingSuggestionView struct {
+	Value string `json:"value"`
+	Count uint64 `json:"count"`
+}
+
+// Gets building suggestion view from suggestion logic model.
+func getBuildingSuggestionView(
+		suggestion *logic.BuildingSuggestion) *BuildingSuggestionView {
+	return &BuildingSuggestionView{
+		Value: suggestion.Value,
+		Count: suggestion.Count,
+	}
 }
 
 // Gets building view from building domain model.
 func getBuildingView(building *domain.Building) *BuildingView {
-	return &BuildingView{
+	view := &BuildingView{
 		Id: building.Id,
 		Name: building.Info.Name,
 		City: building.Info.City,
 		HandoverYear: building.Info.HandoverYear,
 		FloorsCount: building.Info.FloorsCount,
 	}
+	if building.ExternalId != nil {
+		view.ExternalSource = building.ExternalId.Source
+		view.ExternalId = building.ExternalId.Id
+	}
+	return view
 }
 
-// Extracts building filter values from passed context or returns an error if
-// at least one query contains invalid value.
-func extractFilters(c *gin.Context) (*logic.BuildingFilters, error) {
-	// Parse city filter.
-	cityFilter := extractStringFilter(c, "city")
-
-	// Parse handover year filter.
-	handoverYearFilter, err := extractUInt64Filter(c, "handover_year")
-	if err != nil {
-		return nil, err
+// Extracts field to suggest values of from passed context or returns an error
+// if it is invalid.
+func extractSuggestField(c *gin.Context) (logic.BuildingField, error) {
+	field, err := logic.ParseBuildingField(c.Query("field"))
+	if err != nil || !slices.Contains(logic.SuggestableBuildingFields, field) {
+		return "", fmt.Errorf(
+			"unknown suggestion field %q, allowed fields: %s",
+			c.Query("field"),
+			joinBuildingFields(logic.SuggestableBuildingFields))
 	}
+	return field, nil
+}
 
-	// Parse floors count filter.
-	floorsCountFilter, err := extractUInt64Filter(c, "floors_count")
+// Extracts building identifier from path parameters of passed context or
+// returns an error if it is invalid.
+func extractId(c *gin.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		return nil, err
+		return 0, fmt.Errorf("building id is not int64 type")
 	}
-
-	return logic.NewBuildingFilters(
-		cityFilter, handoverYearFilter, floorsCountFilter), nil
+	return id, nil
 }
 
-func extractStringFilter(c *gin.Context, name string) *string {
-	value := c.Query(name)
-	if value == "" {
-		return nil
-	}
-	return &value
+// Statuses of the responses by kinds of the logic errors.
+var errorKindStatuses = map[error]int{
+	logic.ErrInvalidArgument: http.StatusBadRequest,
+	logic.ErrNotFound: http.StatusNotFound,
+	logic.ErrConflict: http.StatusConflict,
+	logic.ErrValidation: http.StatusUnprocessableEntity,
+	logic.ErrUnavailable: http.StatusServiceUnavailable,
 }
 
-// Extracts uint64 filter by its name from passed context or returns an error
-// if query contains invalid value.
-func extractUInt64Filter(c *gin.Context, name string) (*uint64, error) {
-	// Get query string from context.
-	str := c.Query(name)
-	if str == "" {
-		return nil, nil
+// Pushes an error returned by the service to the passed context as a problem.
+// Errors of the known kinds are converted to the corresponding status codes
+// with problem types of their machine-readable codes, others are considered
+// internal.
+func pushServiceError(c *gin.Context, err error) {
+	var logicErr *logic.Error
+	if !errors.As(err, &logicErr) {
+		c.Error(err)
+		NewProblem(http.StatusInternalServerError, "internal error").Push(c)
+		return
+	}
+	status, ok := errorKindStatuses[logicErr.Kind]
+	if !ok {
+		c.Error(err)
+		NewProblem(http.StatusInternalServerError, "internal error").Push(c)
+		return
 	}
 
-	// Try to parse uint64 value from query string.
-	value, err := strconv.ParseUint(str, 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("filter %s is not uint64 type", name)
+	// Wrapping errors may describe the storage or the arguments in the internal
+	// form, so only the message is sent to the client and the whole error is
+	// logged.
+	c.Error(err)
+
+	problem := NewProblem(status, logicErr.Message).WithType(logicErr.Code)
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		problem.WithFieldErrors(validationErr.Errors)
 	}
-	return &value, nil
+	problem.Push(c)
 }
@@ -0,0 +1,76 @@
+package ginapi
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+const (
+	// Minimum similarity of names if it is not passed.
+	defaultMinSimilarity = 0.8
+
+	// Maximum difference of floors counts if it is not passed.
+	defaultMaxFloorsDifference = 2
+
+	// Duplicates count if limit is not passed.
+	defaultDuplicatesLimit = 100
+
+	// Maximum allowed duplicates count.
+	maxDuplicatesLimit = 1000
+)
+
+// Pair of likely duplicate buildings JSON view to make responses.
+type BuildingDuplicateView struct {
+	Building *BuildingView  `json:"building"`
+	Duplicate *BuildingView `json:"duplicate"`
+	Similarity float64      `json:"similarity"`
+}
+
+// Merge JSON input of the duplicate into a building.
+type BuildingMergeBody struct {
+	DuplicateId int64 `json:"duplicate_id" binding:"required"`
+}
+
+// Converts pairs of likely duplicate buildings to their JSON views.
+func getBuildingDuplicateViews(
+		duplicates []*logic.BuildingDuplicate) []*BuildingDuplicateView {
+	views := make([]*BuildingDuplicateView, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		views = append(views, &BuildingDuplicateView{
+			Building: getBuildingView(duplicate.Building),
+			Duplicate: getBuildingView(duplicate.Duplicate),
+			Similarity: duplicate.Similarity,
+		})
+	}
+	return views
+}
+
+// Extracts duplicate criteria from passed context or returns an error if at
+// least one query contains invalid value.
+func extractDuplicateCriteria(c *gin.Context) (*logic.DuplicateCriteria, error) {
+	// Parse minimum similarity.
+	minSimilarity := defaultMinSimilarity
+	if str := c.Query("min_similarity"); str != "" {
+		var err error
+		minSimilarity, err = strconv.ParseFloat(str, 64)
+		if err != nil || minSimilarity < 0 || minSimilarity > 1 {
+			return nil, fmt.Errorf("min_similarity must be between 0 and 1")
+		}
+	}
+
+	// Parse maximum floors difference.
+	maxFloorsDifference, err := extractUInt64Filter(c, "max_floors_difference")
+	if err != nil {
+		return nil, err
+	}
+	if maxFloorsDifference == nil {
+		value := uint64(defaultMaxFloorsDifference)
+		maxFloorsDifference = &value
+	}
+
+	return logic.NewDuplicateCriteria(
+		minSimilarity, *maxFloorsDifference), nil
+}
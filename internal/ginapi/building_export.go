@@ -0,0 +1,160 @@
+package ginapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+)
+
+// Formats of the exported buildings.
+const (
+	exportFormatCSV = "csv"
+	exportFormatNDJSON = "ndjson"
+	exportFormatXLSX = "xlsx"
+)
+
+// Content types of the export formats.
+var exportContentTypes = map[string]string{
+	exportFormatCSV: "text/csv",
+	exportFormatNDJSON: "application/x-ndjson",
+	exportFormatXLSX:
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// Names of the exported columns in header.
+var exportColumns = []string{
+	"id",
+	"name",
+	"city",
+	"handover_year",
+	"floors_count",
+	"external_source",
+	"external_id",
+}
+
+// Encoder of the exported buildings. Buildings are written as they are
+// encoded.
+type buildingEncoder interface {
+	// Encode must write a building or return an error.
+	Encode(building *domain.Building) error
+
+	// Close must write buffered data and the end of the export.
+	Close() error
+}
+
+// Encoder of the buildings to CSV with header.
+type csvBuildingEncoder struct {
+	writer *csv.Writer
+}
+
+func (encoder *csvBuildingEncoder) Encode(building *domain.Building) error {
+	externalSource, externalId := getExportExternalId(building)
+	return encoder.writer.Write([]string{
+		strconv.FormatInt(building.Id, 10),
+		building.Info.Name,
+		building.Info.City,
+		strconv.FormatUint(building.Info.HandoverYear, 10),
+		strconv.FormatUint(building.Info.FloorsCount, 10),
+		externalSource,
+		externalId,
+	})
+}
+
+func (encoder *csvBuildingEncoder) Close() error {
+	encoder.writer.Flush()
+	return encoder.writer.Error()
+}
+
+// Encoder of the buildings to newline delimited JSON views.
+type ndjsonBuildingEncoder struct {
+	encoder *json.Encoder
+}
+
+func (encoder *ndjsonBuildingEncoder) Encode(building *domain.Building) error {
+	return encoder.encoder.Encode(getBuildingView(building))
+}
+
+func (encoder *ndjsonBuildingEncoder) Close() error {
+	return nil
+}
+
+// Encoder of the buildings to XLSX worksheet with header.
+type xlsxBuildingEncoder struct {
+	writer *xlsxWriter
+}
+
+func (encoder *xlsxBuildingEncoder) Encode(building *domain.Building) error {
+	externalSource, externalId := getExportExternalId(building)
+	return encoder.writer.WriteRow(
+		building.Id,
+		building.Info.Name,
+		building.Info.City,
+		building.Info.HandoverYear,
+		building.Info.FloorsCount,
+		externalSource,
+		externalId)
+}
+
+func (encoder *xlsxBuildingEncoder) Close() error {
+	return encoder.writer.Close()
+}
+
+// Returns source and identifier of the external identifier of passed building
+// or empty strings if it is not set.
+func getExportExternalId(building *domain.Building) (string, string) {
+	if building.ExternalId == nil {
+		return "", ""
+	}
+	return building.ExternalId.Source, building.ExternalId.Id
+}
+
+// Creates a new encoder of passed format, which writes to passed writer, or
+// returns an error.
+func newBuildingEncoder(format string, w io.Writer) (buildingEncoder, error) {
+	switch format {
+	case exportFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(exportColumns); err != nil {
+			return nil, fmt.Errorf("failed to write CSV header: %v", err)
+		}
+		return &csvBuildingEncoder{writer: writer}, nil
+	case exportFormatNDJSON:
+		return &ndjsonBuildingEncoder{encoder: json.NewEncoder(w)}, nil
+	case exportFormatXLSX:
+		writer, err := newXLSXWriter(w)
+		if err != nil {
+			return nil, err
+		}
+		header := make([]any, len(exportColumns))
+		for i, column := range exportColumns {
+			header[i] = column
+		}
+		if err := writer.WriteRow(header...); err != nil {
+			return nil, fmt.Errorf("failed to write XLSX header: %v", err)
+		}
+		return &xlsxBuildingEncoder{writer: writer}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// Extracts export format from passed query value or returns an error if format
+// is unknown. CSV is used by default.
+func parseExportFormat(str string) (string, error) {
+	if str == "" {
+		return exportFormatCSV, nil
+	}
+	if _, ok := exportContentTypes[str]; !ok {
+		return "", fmt.Errorf(
+			"unknown export format %q, allowed formats: %s, %s, %s",
+			str,
+			exportFormatCSV,
+			exportFormatNDJSON,
+			exportFormatXLSX)
+	}
+	return str, nil
+}
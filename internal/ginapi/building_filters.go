@@ -0,0 +1,403 @@
+package ginapi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Names of the fields that can be filtered.
+var filterableFields = []string{"city", "handover_year", "floors_count"}
+
+// Operators that can be used to filter string fields.
+var stringFilterOperators = []logic.FilterOperator{
+	logic.FilterOperatorEq,
+	logic.FilterOperatorNe,
+	logic.FilterOperatorIn,
+	logic.FilterOperatorNotIn,
+}
+
+const (
+	// Page size if limit is not passed.
+	defaultBuildingsLimit = 100
+
+	// Maximum allowed page size.
+	maxBuildingsLimit = 1000
+
+	// Suggestions count if limit is not passed.
+	defaultSuggestionsLimit = 10
+
+	// Maximum allowed suggestions count.
+	maxSuggestionsLimit = 50
+)
+
+// Extracts building filter values from passed context or returns an error if
+// at least one query contains invalid value.
+//
+// Filter query is either "<field>=<value>", which checks equality, or
+// "<field>[<operator>]=<value>". Operators "in" and "nin" accept
+// comma-separated values.
+func extractFilters(c *gin.Context) (*logic.BuildingFilters, error) {
+	// Parse city filter.
+	cityFilter, err := extractFilter(
+		c, "city", stringFilterOperators, parseString)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse handover year filter.
+	handoverYearFilter, err := extractFilter(
+		c, "handover_year", logic.FilterOperators, parseUInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse floors count filter.
+	floorsCountFilter, err := extractFilter(
+		c, "floors_count", logic.FilterOperators, parseUInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that there are no filters with unknown fields or operators.
+	if err := checkFilterQueries(c, filterableFields); err != nil {
+		return nil, err
+	}
+
+	filters := logic.NewBuildingFilters(
+		cityFilter, handoverYearFilter, floorsCountFilter)
+
+	// Parse search parameters.
+	search, err := extractSearch(c)
+	if err != nil {
+		return nil, err
+	}
+	filters.WithSearch(search)
+
+	return filters, nil
+}
+
+// Extracts building filter values, sort keys and pagination parameters from
+// passed context or returns an error if at least one query contains invalid
+// value.
+func extractPageFilters(c *gin.Context) (*logic.BuildingFilters, error) {
+	// Parse filter values and sort keys.
+	filters, err := extractSortedFilters(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse pagination parameters.
+	if err := extractPagination(c, filters); err != nil {
+		return nil, err
+	}
+
+	return filters, nil
+}
+
+// Extracts building filter values and sort keys from passed context or returns
+// an error if at least one query contains invalid value.
+func extractSortedFilters(c *gin.Context) (*logic.BuildingFilters, error) {
+	// Parse filter values.
+	filters, err := extractFilters(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse sort keys.
+	sort, err := extractSort(c)
+	if err != nil {
+		return nil, err
+	}
+	filters.WithSort(sort)
+
+	return filters, nil
+}
+
+// Extracts pagination parameters from passed context to the filters or returns
+// an error if at least one query contains invalid value. Limit is always set.
+func extractPagination(c *gin.Context, filters *logic.BuildingFilters) error {
+	// Parse limit.
+	limit, err := extractLimit(c, defaultBuildingsLimit, maxBuildingsLimit)
+	if err != nil {
+		return err
+	}
+
+	// Parse offset.
+	offset, err := extractUInt64Filter(c, "offset")
+	if err != nil {
+		return err
+	}
+
+	// Parse cursor if it is set.
+	var after *logic.BuildingCursor
+	if str := c.Query("after"); str != "" {
+		after, err = logic.DecodeBuildingCursor(str)
+		if err != nil {
+			return err
+		}
+	}
+
+	filters.WithPagination(&limit, offset, after)
+	return nil
+}
+
+// Extracts text search parameters from passed context or returns an error if
+// at least one query contains invalid value. Nil is returned if search query
+// is not set.
+func extractSearch(c *gin.Context) (*logic.BuildingSearch, error) {
+	// Get search query from context.
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return nil, nil
+	}
+
+	// Try to parse city search flag.
+	includeCity, err := extractBoolQuery(c, "search_city")
+	if err != nil {
+		return nil, err
+	}
+
+	return logic.NewBuildingSearch(query, includeCity), nil
+}
+
+// Extracts sort keys from passed context or returns an error if at least one
+// of them is invalid.
+//
+// Sort query is a comma-separated list of building view fields. Field with
+// "-" prefix is sorted in descending order.
+func extractSort(c *gin.Context) ([]*logic.BuildingSortKey, error) {
+	// Get query string from context.
+	str := c.Query("sort")
+	if str == "" {
+		return nil, nil
+	}
+
+	var sort []*logic.BuildingSortKey
+	used := make(map[logic.BuildingField]bool)
+	for _, name := range strings.Split(str, ",") {
+		// Trim descending order prefix.
+		descending := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+
+		// Try to parse sort field.
+		field, err := logic.ParseBuildingField(name)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"unknown sort field %q, allowed fields: %s",
+				name,
+				joinBuildingFields(logic.BuildingFields))
+		}
+
+		// Field can be used only once.
+		if used[field] {
+			return nil, fmt.Errorf("sort field %s is used twice", name)
+		}
+		used[field] = true
+
+		sort = append(sort, logic.NewBuildingSortKey(field, descending))
+	}
+
+	return sort, nil
+}
+
+// Extracts fields to count facets of from passed context or returns an error
+// if at least one of them is invalid.
+func extractFacetFields(c *gin.Context) ([]logic.BuildingField, error) {
+	// Get query string from context.
+	str := c.Query("facets")
+	if str == "" {
+		return nil, nil
+	}
+
+	var fields []logic.BuildingField
+	for _, name := range strings.Split(str, ",") {
+		// Try to parse facet field.
+		field, err := logic.ParseBuildingField(name)
+		if err != nil || !slices.Contains(logic.FacetableBuildingFields, field) {
+			return nil, fmt.Errorf(
+				"unknown facet field %q, allowed fields: %s",
+				name,
+				joinBuildingFields(logic.FacetableBuildingFields))
+		}
+
+		// Skip repeated fields.
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
+
+// Joins names of passed fields with commas.
+func joinBuildingFields(fields []logic.BuildingField) string {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, string(field))
+	}
+	return strings.Join(names, ", ")
+}
+
+// Extracts limit query from passed context, using default one if it is not
+// set, or returns an error if it is invalid or greater than maximum.
+func extractLimit(
+		c *gin.Context, defaultLimit, maxLimit uint64) (uint64, error) {
+	// Try to parse limit query.
+	limit, err := extractUInt64Filter(c, "limit")
+	if err != nil {
+		return 0, err
+	}
+
+	// Check limit bounds.
+	if limit == nil {
+		return defaultLimit, nil
+	} else if *limit == 0 || *limit > maxLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+	}
+	return *limit, nil
+}
+
+// Extracts bool query by its name from passed context or returns an error if
+// query contains invalid value. Absent query is considered false.
+func extractBoolQuery(c *gin.Context, name string) (bool, error) {
+	// Get query string from context.
+	str := c.Query(name)
+	if str == "" {
+		return false, nil
+	}
+
+	// Try to parse bool value from query string.
+	value, err := strconv.ParseBool(str)
+	if err != nil {
+		return false, fmt.Errorf("query %s is not bool type", name)
+	}
+	return value, nil
+}
+
+// Extracts filter on a field with passed name from context or returns an error
+// if at least one query contains invalid or no value or operator which is not
+// in allowed ones. Nil is returned if there are no filter queries.
+func extractFilter[T logic.FilterValue](
+		c *gin.Context,
+		name string,
+		operators []logic.FilterOperator,
+		parse func(string) (T, error)) (*logic.Filter[T], error) {
+	var filter *logic.Filter[T]
+	query := c.Request.URL.Query()
+
+	for _, operator := range logic.FilterOperators {
+		// Get query string of the operator. Plain field query means equality
+		// and it is ignored if empty.
+		key := fmt.Sprintf("%s[%s]", name, operator)
+		if operator == logic.FilterOperatorEq && !query.Has(key) {
+			key = name
+			if query.Get(key) == "" {
+				continue
+			}
+		}
+		if !query.Has(key) {
+			continue
+		}
+
+		// Operator query without values is rejected instead of being ignored,
+		// because empty set of included values would match nothing.
+		str := query.Get(key)
+		if str == "" {
+			return nil, fmt.Errorf("filter %s has no value", key)
+		}
+
+		// Check that operator is allowed for the field.
+		if !slices.Contains(operators, operator) {
+			return nil, fmt.Errorf(
+				"operator %s is not allowed for filter %s", operator, name)
+		}
+
+		// Split multiple values operators.
+		strs := []string{str}
+		if operator == logic.FilterOperatorIn ||
+				operator == logic.FilterOperatorNotIn {
+			strs = strings.Split(str, ",")
+		}
+
+		// Try to parse all operator values.
+		values := make([]T, 0, len(strs))
+		for _, str := range strs {
+			value, err := parse(str)
+			if err != nil {
+				return nil, fmt.Errorf("filter %s %v", key, err)
+			}
+			values = append(values, value)
+		}
+
+		// Set condition to the filter.
+		if filter == nil {
+			filter = &logic.Filter[T]{}
+		}
+		filter.Set(operator, values)
+	}
+
+	return filter, nil
+}
+
+// Checks that all queries with filter syntax "<field>[<operator>]" refer to
+// passed fields and known operators.
+func checkFilterQueries(c *gin.Context, fields []string) error {
+	for key := range c.Request.URL.Query() {
+		// Skip queries without filter syntax.
+		name, rest, found := strings.Cut(key, "[")
+		if !found || !strings.HasSuffix(rest, "]") {
+			continue
+		}
+
+		// Check filter field and operator.
+		if !slices.Contains(fields, name) {
+			return fmt.Errorf(
+				"unknown filter field %q, allowed fields: %s",
+				name,
+				strings.Join(fields, ", "))
+		}
+		operator := strings.TrimSuffix(rest, "]")
+		if _, err := logic.ParseFilterOperator(operator); err != nil {
+			return fmt.Errorf("unknown filter operator %q", operator)
+		}
+	}
+
+	return nil
+}
+
+// Parses string filter value.
+func parseString(str string) (string, error) {
+	return str, nil
+}
+
+// Parses uint64 filter value.
+func parseUInt64(str string) (uint64, error) {
+	value, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return 0, errors.New("is not uint64 type")
+	}
+	return value, nil
+}
+
+// Extracts uint64 filter by its name from passed context or returns an error
+// if query contains invalid value.
+func extractUInt64Filter(c *gin.Context, name string) (*uint64, error) {
+	// Get query string from context.
+	str := c.Query(name)
+	if str == "" {
+		return nil, nil
+	}
+
+	// Try to parse uint64 value from query string.
+	value, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("filter %s is not uint64 type", name)
+	}
+	return &value, nil
+}
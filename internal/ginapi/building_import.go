@@ -0,0 +1,200 @@
+package ginapi
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+)
+
+// Content type of the imported buildings.
+const csvContentType = "text/csv"
+
+// Fields of the building body that are read from CSV columns.
+var importFields = []string{"name", "city", "handover_year", "floors_count"}
+
+// Report of the import JSON view to make responses.
+//
+// Invalid lines are skipped. Imported count is always zero in dry run.
+type BuildingImportView struct {
+	DryRun bool                       `json:"dry_run"`
+	Valid int                         `json:"valid"`
+	Invalid int                       `json:"invalid"`
+	Imported uint64                   `json:"imported"`
+	Errors []*BuildingImportErrorView `json:"errors"`
+}
+
+// Errors of the invalid CSV line JSON view to make responses.
+type BuildingImportErrorView struct {
+	Line int        `json:"line"`
+	Errors []string `json:"errors"`
+}
+
+// Source of building information read from CSV. Invalid lines are skipped and
+// added to the report.
+type csvBuildingSource struct {
+	reader *csv.Reader
+
+	// Indexes of CSV columns by building body fields.
+	columns map[string]int
+
+	info *domain.BuildingInfo
+	err error
+	report *BuildingImportView
+}
+
+func (source *csvBuildingSource) Next() bool {
+	for {
+		// Try to read the next record.
+		record, err := source.reader.Read()
+		if err == io.EOF {
+			return false
+		}
+
+		// Lines with wrong fields count are invalid, but others can be read.
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) &&
+				errors.Is(parseErr.Err, csv.ErrFieldCount) {
+			source.addInvalid(parseErr.Line, []string{"wrong number of fields"})
+			continue
+		} else if err != nil {
+			source.err = err
+			return false
+		}
+		line, _ := source.reader.FieldPos(0)
+
+		// Try to parse and validate the record.
+		info, messages := source.parseRecord(record)
+		if len(messages) > 0 {
+			source.addInvalid(line, messages)
+			continue
+		}
+
+		source.info = info
+		source.report.Valid++
+		return true
+	}
+}
+
+func (source *csvBuildingSource) Info() *domain.BuildingInfo {
+	return source.info
+}
+
+func (source *csvBuildingSource) Err() error {
+	return source.err
+}
+
+// Adds invalid line with its error messages to the report.
+func (source *csvBuildingSource) addInvalid(line int, messages []string) {
+	source.report.Invalid++
+	source.report.Errors = append(source.report.Errors, &BuildingImportErrorView{
+		Line: line,
+		Errors: messages,
+	})
+}
+
+// Parses CSV record to building information and validates it according to
+// building body rules. Returns error messages if record is invalid.
+func (source *csvBuildingSource) parseRecord(
+		record []string) (*domain.BuildingInfo, []string) {
+	var (
+		body BuildingBody
+		messages []string
+	)
+
+	// Parse values of the columns.
+	body.Name = record[source.columns["name"]]
+	body.City = record[source.columns["city"]]
+	for _, field := range []struct {
+		name string
+		value *uint64
+	}{
+		{"handover_year", &body.HandoverYear},
+		{"floors_count", &body.FloorsCount},
+	} {
+		str := strings.TrimSpace(record[source.columns[field.name]])
+		if str == "" {
+			continue
+		}
+		value, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			messages = append(
+				messages, fmt.Sprintf("%s is not uint64 type", field.name))
+			continue
+		}
+		*field.value = value
+	}
+	if len(messages) > 0 {
+		return nil, messages
+	}
+
+	// Validate information according to the domain rules.
+	info := body.toInfo()
+	domain.NormalizeBuildingInfo(info)
+	var validationErr *domain.ValidationError
+	if err := domain.ValidateBuildingInfo(info); errors.As(err, &validationErr) {
+		for _, fieldErr := range validationErr.Errors {
+			messages = append(messages, fieldErr.Error())
+		}
+		return nil, messages
+	}
+
+	return info, nil
+}
+
+// Creates a new CSV building source which reads passed reader according to
+// import parameters from context. Returns an error if the parameters are
+// invalid or CSV header does not contain mapped columns.
+func newCSVBuildingSource(
+		c *gin.Context,
+		reader io.Reader,
+		report *BuildingImportView) (*csvBuildingSource, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.ReuseRecord = true
+
+	// Try to extract fields delimiter.
+	if str := c.Query("delimiter"); str != "" {
+		delimiter, size := utf8.DecodeRuneInString(str)
+		if size != len(str) {
+			return nil, errors.New("delimiter must be a single character")
+		}
+		csvReader.Comma = delimiter
+	}
+
+	// Try to read header.
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %v", err)
+	}
+
+	// Find columns of the fields. Column of the field is named by the field
+	// itself unless other name is passed.
+	columns := make(map[string]int, len(importFields))
+	for _, field := range importFields {
+		name := c.DefaultQuery(fmt.Sprintf("column[%s]", field), field)
+		index := -1
+		for i, column := range header {
+			if strings.TrimSpace(column) == name {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return nil, fmt.Errorf(
+				"CSV header does not contain column %q of field %s", name, field)
+		}
+		columns[field] = index
+	}
+
+	return &csvBuildingSource{
+		reader: csvReader,
+		columns: columns,
+		report: report,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package ginapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Header with the correlation identifier of the request.
+	correlationIdHeader = "X-Request-Id"
+	// Key of the correlation identifier in the context.
+	correlationIdKey = "correlation_id"
+	// Maximum allowed length of the correlation identifier passed by client.
+	maxCorrelationIdLength = 128
+	// Length of the generated correlation identifier in bytes before encoding.
+	correlationIdSize = 16
+)
+
+// Middleware to set correlation identifier of the request. Identifier passed
+// by the client is used if it is valid, otherwise a new one is generated. It
+// is returned in the response header and in the problem details.
+func correlationIdMiddleware(c *gin.Context) {
+	id := c.GetHeader(correlationIdHeader)
+	if !isValidCorrelationId(id) {
+		id = newCorrelationId()
+	}
+
+	c.Set(correlationIdKey, id)
+	c.Header(correlationIdHeader, id)
+	c.Next()
+}
+
+// Gets correlation identifier of the request or empty string if it is not
+// set.
+func getCorrelationId(c *gin.Context) string {
+	return c.GetString(correlationIdKey)
+}
+
+// Generates a new random correlation identifier.
+func newCorrelationId() string {
+	bytes := make([]byte, correlationIdSize)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// Checks that correlation identifier passed by client is not empty, not too
+// long and consists of printable ASCII characters, so it can be safely
+// returned in the header.
+func isValidCorrelationId(id string) bool {
+	if id == "" || len(id) > maxCorrelationIdLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
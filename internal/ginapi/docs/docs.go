@@ -26,7 +26,7 @@ const docTemplate = `{
     "paths": {
         "/buildings": {
             "get": {
-                "description": "Gets all buildings according to passed filter paramters",
+                "description": "Gets a page of buildings according to passed filter, search, sort and pagination paramters. Buildings with equal sort keys are ordered by identifier. Search results without sort keys are ordered by relevance. Cursor of the next page is returned in headers.",
                 "consumes": [
                     "application/json"
                 ],
@@ -41,88 +41,1847 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "city filter",
+                        "description": "city filter, same as city[eq]",
                         "name": "city",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "city inequality filter",
+                        "name": "city[ne]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated cities to include",
+                        "name": "city[in]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated cities to exclude",
+                        "name": "city[nin]",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "handover year filter",
+                        "description": "handover year filter, same as handover_year[eq]",
                         "name": "handover_year",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "floors count filter",
+                        "description": "handover year inequality filter",
+                        "name": "handover_year[ne]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum handover year, exclusive",
+                        "name": "handover_year[gt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum handover year, inclusive",
+                        "name": "handover_year[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum handover year, exclusive",
+                        "name": "handover_year[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum handover year, inclusive",
+                        "name": "handover_year[lte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated handover years to include",
+                        "name": "handover_year[in]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated handover years to exclude",
+                        "name": "handover_year[nin]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "floors count filter, same as floors_count[eq]",
                         "name": "floors_count",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "floors count inequality filter",
+                        "name": "floors_count[ne]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum floors count, exclusive",
+                        "name": "floors_count[gt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum floors count, inclusive",
+                        "name": "floors_count[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum floors count, exclusive",
+                        "name": "floors_count[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum floors count, inclusive",
+                        "name": "floors_count[lte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated floors counts to include",
+                        "name": "floors_count[in]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated floors counts to exclude",
+                        "name": "floors_count[nin]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "text search over names, tolerant to typos and partial names",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether to search over cities too",
+                        "name": "search_city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated fields to sort by, descending if prefixed with '-', e.g. -handover_year,name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum page size, 100 by default, 1000 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "count of buildings to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the previous page to get the next one",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether to count all filtered buildings",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ginapi.BuildingView"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "reference to the next page with rel=next if the page is full"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "cursor of the next page if the page is full"
+                            },
+                            "X-Total-Count": {
+                                "type": "int",
+                                "description": "count of all filtered buildings if with_total is set"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new building using passed data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Creates a new building",
+                "operationId": "create-building",
+                "parameters": [
+                    {
+                        "description": "Create building",
+                        "name": "building",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingBody"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "key to identify retries of the same request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingView"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/buildings/batch": {
+            "post": {
+                "description": "Creates buildings using passed array of data and returns status of each of them. Atomic batch is created entirely or not created at all. Otherwise, valid buildings are created even if others are not.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Creates a batch of buildings",
+                "operationId": "create-buildings-batch",
+                "parameters": [
+                    {
+                        "description": "Create buildings",
+                        "name": "buildings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ginapi.BuildingBody"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether to create all buildings or none of them, true by default",
+                        "name": "atomic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "key to identify retries of the same request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "all buildings are created",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingBatchView"
+                        }
+                    },
+                    "207": {
+                        "description": "some buildings of non-atomic batch are not created",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingBatchView"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "atomic batch is not created",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingBatchView"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/buildings/by-external/{source}/{id}": {
+            "put": {
+                "description": "Creates a building with passed identifier in passed external system or replaces information of the existing one, so repeated synchronization does not duplicate buildings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Creates or updates a building by its external identifier",
+                "operationId": "upsert-building",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "name of the external system",
+                        "name": "source",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "building identifier in the external system",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create or update building",
+                        "name": "building",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingBody"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "key to identify retries of the same request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "building is updated",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingView"
+                        }
+                    },
+                    "201": {
+                        "description": "building is created",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingView"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/buildings/duplicates": {
+            "get": {
+                "description": "Finds pairs of buildings of the same city and handover year with similar names and close floors counts. Names are compared ignoring case, punctuation, words order and words like \"ЖК\". The older building of a pair is suggested as canonical.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Finds likely duplicate buildings",
+                "operationId": "find-building-duplicates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "city filter, also city[\u003cop\u003e] as in getall-buildings",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "handover year filter, also handover_year[\u003cop\u003e] as in getall-buildings",
+                        "name": "handover_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "floors count filter, also floors_count[\u003cop\u003e] as in getall-buildings",
+                        "name": "floors_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "minimum similarity of names from 0 to 1, 0.8 by default",
+                        "name": "min_similarity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum difference of floors counts, 2 by default",
+                        "name": "max_floors_difference",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum count of pairs, 100 by default, 1000 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ginapi.BuildingDuplicateView"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/buildings/export": {
+            "get": {
+                "description": "Streams all buildings according to passed filter, search and sort parameters in passed format without pagination. Buildings with equal sort keys are ordered by identifier.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Exports buildings",
+                "operationId": "export-buildings",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "export format, csv by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "city filter, same as city[eq]",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "city inequality filter",
+                        "name": "city[ne]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated cities to include",
+                        "name": "city[in]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated cities to exclude",
+                        "name": "city[nin]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "handover year filter, same as handover_year[eq]",
+                        "name": "handover_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "handover year inequality filter",
+                        "name": "handover_year[ne]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum handover year, exclusive",
+                        "name": "handover_year[gt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum handover year, inclusive",
+                        "name": "handover_year[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum handover year, exclusive",
+                        "name": "handover_year[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum handover year, inclusive",
+                        "name": "handover_year[lte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated handover years to include",
+                        "name": "handover_year[in]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated handover years to exclude",
+                        "name": "handover_year[nin]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "floors count filter, same as floors_count[eq]",
+                        "name": "floors_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "floors count inequality filter",
+                        "name": "floors_count[ne]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum floors count, exclusive",
+                        "name": "floors_count[gt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum floors count, inclusive",
+                        "name": "floors_count[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum floors count, exclusive",
+                        "name": "floors_count[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum floors count, inclusive",
+                        "name": "floors_count[lte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated floors counts to include",
+                        "name": "floors_count[in]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated floors counts to exclude",
+                        "name": "floors_count[nin]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "text search over names, tolerant to typos and partial names",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether to search over cities too",
+                        "name": "search_city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated fields to sort by, descending if prefixed with '-', e.g. -handover_year,name",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/buildings/import": {
+            "post": {
+                "description": "Imports buildings from CSV with header. Columns are named by building fields unless other names are mapped. Invalid lines are skipped and reported. Dry run only validates lines without creating buildings.",
+                "consumes": [
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Imports buildings from CSV",
+                "operationId": "import-buildings",
+                "parameters": [
+                    {
+                        "description": "CSV with buildings",
+                        "name": "buildings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether to only validate lines",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "fields delimiter, comma by default",
+                        "name": "delimiter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "column of the name",
+                        "name": "column[name]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "column of the city",
+                        "name": "column[city]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "column of the handover year",
+                        "name": "column[handover_year]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "column of the floors count",
+                        "name": "column[floors_count]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "key to identify retries of the same request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry run",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingImportView"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingImportView"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/buildings/page": {
+            "get": {
+                "description": "Gets a page of buildings like getall-buildings, but in an envelope with cursor of the next page and requested facets.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Gets a page of buildings",
+                "operationId": "getpage-buildings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "city filter, same as city[eq]",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "city inequality filter",
+                        "name": "city[ne]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated cities to include",
+                        "name": "city[in]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated cities to exclude",
+                        "name": "city[nin]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "handover year filter, same as handover_year[eq]",
+                        "name": "handover_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "handover year inequality filter",
+                        "name": "handover_year[ne]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum handover year, exclusive",
+                        "name": "handover_year[gt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum handover year, inclusive",
+                        "name": "handover_year[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum handover year, exclusive",
+                        "name": "handover_year[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum handover year, inclusive",
+                        "name": "handover_year[lte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated handover years to include",
+                        "name": "handover_year[in]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated handover years to exclude",
+                        "name": "handover_year[nin]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "floors count filter, same as floors_count[eq]",
+                        "name": "floors_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "floors count inequality filter",
+                        "name": "floors_count[ne]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum floors count, exclusive",
+                        "name": "floors_count[gt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum floors count, inclusive",
+                        "name": "floors_count[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum floors count, exclusive",
+                        "name": "floors_count[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum floors count, inclusive",
+                        "name": "floors_count[lte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated floors counts to include",
+                        "name": "floors_count[in]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated floors counts to exclude",
+                        "name": "floors_count[nin]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "text search over names, tolerant to typos and partial names",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether to search over cities too",
+                        "name": "search_city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated fields to sort by, descending if prefixed with '-', e.g. -handover_year,name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum page size, 100 by default, 1000 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "count of buildings to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the previous page to get the next one",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated fields to count buildings by their values, e.g. city,handover_year",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether to count all filtered buildings",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingPageView"
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "int",
+                                "description": "count of all filtered buildings if with_total is set"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/buildings/stats": {
+            "get": {
+                "description": "Gets counts of buildings grouped by city and by handover year and floors count statistics according to passed filter paramters",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Gets building statistics",
+                "operationId": "getstats-buildings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "city filter, same as city[eq]",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "city inequality filter",
+                        "name": "city[ne]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated cities to include",
+                        "name": "city[in]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated cities to exclude",
+                        "name": "city[nin]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "handover year filter, same as handover_year[eq]",
+                        "name": "handover_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "handover year inequality filter",
+                        "name": "handover_year[ne]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum handover year, exclusive",
+                        "name": "handover_year[gt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum handover year, inclusive",
+                        "name": "handover_year[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum handover year, exclusive",
+                        "name": "handover_year[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum handover year, inclusive",
+                        "name": "handover_year[lte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated handover years to include",
+                        "name": "handover_year[in]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated handover years to exclude",
+                        "name": "handover_year[nin]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "floors count filter, same as floors_count[eq]",
+                        "name": "floors_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "floors count inequality filter",
+                        "name": "floors_count[ne]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum floors count, exclusive",
+                        "name": "floors_count[gt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum floors count, inclusive",
+                        "name": "floors_count[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum floors count, exclusive",
+                        "name": "floors_count[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum floors count, inclusive",
+                        "name": "floors_count[lte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated floors counts to include",
+                        "name": "floors_count[in]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma-separated floors counts to exclude",
+                        "name": "floors_count[nin]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "text search over names, tolerant to typos and partial names",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether to search over cities too",
+                        "name": "search_city",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingStatsView"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/buildings/suggest": {
+            "get": {
+                "description": "Gets the most frequent distinct values of building field that start with passed prefix, ignoring case",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Suggests building field values",
+                "operationId": "suggest-buildings",
+                "parameters": [
+                    {
+                        "enum": [
+                            "name",
+                            "city"
+                        ],
+                        "type": "string",
+                        "description": "field to suggest values of",
+                        "name": "field",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "prefix of the values",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum count of values, 10 by default, 50 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ginapi.BuildingSuggestionView"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/buildings/{id}": {
+            "get": {
+                "description": "Gets a building by its identifier",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Gets a building",
+                "operationId": "get-building",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "building identifier",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingView"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces all information of a building with passed data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Updates a building",
+                "operationId": "update-building",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "building identifier",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update building",
+                        "name": "building",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingBody"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "key to identify retries of the same request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingView"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a building by its identifier",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Deletes a building",
+                "operationId": "delete-building",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "building identifier",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "key to identify retries of the same request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Changes only passed fields of a building according to JSON Merge Patch (RFC 7386)",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Partially updates a building",
+                "operationId": "patch-building",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "building identifier",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch building",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingPatchBody"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "key to identify retries of the same request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingView"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/buildings/{id}/merge": {
+            "post": {
+                "description": "Deletes a duplicate building and keeps the building with passed identifier as canonical. External identifier of the duplicate is moved to the building if the latter does not have one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Merges a duplicate into a building",
+                "operationId": "merge-building",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "canonical building identifier",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge duplicate",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.BuildingMergeBody"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "key to identify retries of the same request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/ginapi.BuildingView"
-                            }
+                            "$ref": "#/definitions/ginapi.BuildingView"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/ginapi.Error"
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ginapi.Error"
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/feeds/import": {
             "post": {
-                "description": "Creates a new building using passed data",
+                "description": "Imports complexes of Yandex.Realty style feed as buildings. Complexes are identified by \"yandex-building-id\" or by their names and cities, so reimport of the feed updates buildings instead of duplicating them. Complexes without required information are skipped.",
                 "consumes": [
-                    "application/json"
+                    "text/xml"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "building"
+                    "feed"
                 ],
-                "summary": "Creates a new building",
-                "operationId": "create-building",
+                "summary": "Imports a partner feed",
+                "operationId": "import-feed",
                 "parameters": [
                     {
-                        "description": "Create building",
-                        "name": "building",
+                        "description": "Yandex.Realty style feed",
+                        "name": "feed",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/ginapi.BuildingBody"
+                            "type": "string"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of the partner which supplied the feed",
+                        "name": "source",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "key to identify retries of the same request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ginapi.BuildingView"
+                            "$ref": "#/definitions/ginapi.FeedImportView"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/ginapi.Error"
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{format}": {
+            "get": {
+                "description": "Streams all buildings as an XML feed in the structure of passed real-estate portal. Catalog stores only complexes, so elements the portals require for offers, such as price, creation date and sales agent, are not rendered and must be added by partners. Feed is rendered entirely before it is sent, so its entity tag matches the sent buildings, and it is not downloaded again while the tag matches.",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "feed"
+                ],
+                "summary": "Gets a feed of buildings",
+                "operationId": "get-feed",
+                "parameters": [
+                    {
+                        "enum": [
+                            "yandex.xml",
+                            "avito.xml"
+                        ],
+                        "type": "string",
+                        "description": "portal format, e.g. yandex.xml",
+                        "name": "format",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "entity tag of the previously downloaded feed",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the feed"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "feed is not changed"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ginapi.Error"
+                            "$ref": "#/definitions/ginapi.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ginapi.Problem"
                         }
                     }
                 }
@@ -130,14 +1889,153 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "ginapi.BuildingBatchItemView": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ginapi.FieldErrorView"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "ginapi.BuildingBatchView": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ginapi.BuildingBatchItemView"
+                    }
+                }
+            }
+        },
         "ginapi.BuildingBody": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "floors_count": {
+                    "type": "integer"
+                },
+                "handover_year": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "ginapi.BuildingDuplicateView": {
+            "type": "object",
+            "properties": {
+                "building": {
+                    "$ref": "#/definitions/ginapi.BuildingView"
+                },
+                "duplicate": {
+                    "$ref": "#/definitions/ginapi.BuildingView"
+                },
+                "similarity": {
+                    "type": "number"
+                }
+            }
+        },
+        "ginapi.BuildingFacetsView": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "array",
+                "items": {
+                    "$ref": "#/definitions/ginapi.FacetValueView"
+                }
+            }
+        },
+        "ginapi.BuildingImportErrorView": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "line": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ginapi.BuildingImportView": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ginapi.BuildingImportErrorView"
+                    }
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ginapi.BuildingMergeBody": {
             "type": "object",
             "required": [
-                "city",
-                "floors_count",
-                "handover_year",
-                "name"
+                "duplicate_id"
             ],
+            "properties": {
+                "duplicate_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ginapi.BuildingPageView": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "$ref": "#/definitions/ginapi.BuildingFacetsView"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ginapi.BuildingView"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "ginapi.BuildingPatchBody": {
+            "type": "object",
             "properties": {
                 "city": {
                     "type": "string"
@@ -153,12 +2051,52 @@ const docTemplate = `{
                 }
             }
         },
+        "ginapi.BuildingStatsView": {
+            "type": "object",
+            "properties": {
+                "by_city": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ginapi.CityCountView"
+                    }
+                },
+                "by_handover_year": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ginapi.HandoverYearCountView"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "floors_count": {
+                    "$ref": "#/definitions/ginapi.FloorsCountStatsView"
+                }
+            }
+        },
+        "ginapi.BuildingSuggestionView": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
         "ginapi.BuildingView": {
             "type": "object",
             "properties": {
                 "city": {
                     "type": "string"
                 },
+                "external_id": {
+                    "type": "string"
+                },
+                "external_source": {
+                    "type": "string"
+                },
                 "floors_count": {
                     "type": "integer"
                 },
@@ -173,16 +2111,107 @@ const docTemplate = `{
                 }
             }
         },
-        "ginapi.Error": {
+        "ginapi.CityCountView": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ginapi.FacetValueView": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {}
+            }
+        },
+        "ginapi.FeedImportView": {
             "type": "object",
             "properties": {
-                "code": {
+                "inserted": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated": {
                     "type": "integer"
+                }
+            }
+        },
+        "ginapi.FieldErrorView": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
                 },
                 "message": {
                     "type": "string"
                 }
             }
+        },
+        "ginapi.FloorsCountStatsView": {
+            "type": "object",
+            "properties": {
+                "avg": {
+                    "type": "number"
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "min": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ginapi.HandoverYearCountView": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "handover_year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ginapi.Problem": {
+            "type": "object",
+            "properties": {
+                "correlation_id": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ginapi.FieldErrorView"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,133 @@
+package ginapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+)
+
+// Format of the XML feed of a real-estate portal.
+type feedFormat interface {
+	// NewEncoder must write the beginning of the feed generated at passed time
+	// and return encoder of its buildings or return an error.
+	NewEncoder(w io.Writer, generatedAt time.Time) (buildingEncoder, error)
+}
+
+// Feed formats by names, which are used in feed paths. To support a new portal,
+// implement its format and add it here.
+var feedFormats = map[string]feedFormat{
+	"avito": avitoFeedFormat{},
+	"yandex": yandexFeedFormat{},
+}
+
+// Content type of the rendered feeds.
+const feedContentType = "application/xml; charset=utf-8"
+
+// Encoder of the buildings to weak entity tag of the feed. Generation time is
+// not taken into account, so feed is not downloaded again until the buildings
+// change.
+type feedETagEncoder struct {
+	hash hash.Hash
+}
+
+func (encoder *feedETagEncoder) Encode(building *domain.Building) error {
+	_, err := fmt.Fprintf(
+		encoder.hash,
+		"%d\x00%s\x00%s\x00%d\x00%d\n",
+		building.Id,
+		building.Info.Name,
+		building.Info.City,
+		building.Info.HandoverYear,
+		building.Info.FloorsCount)
+	return err
+}
+
+func (encoder *feedETagEncoder) Close() error {
+	return nil
+}
+
+// Returns entity tag of the encoded buildings.
+func (encoder *feedETagEncoder) ETag() string {
+	return fmt.Sprintf(`W/"%s"`, hex.EncodeToString(encoder.hash.Sum(nil)))
+}
+
+// Encoder of the buildings to XML elements of the feed. Each building is
+// written as soon as it is encoded.
+type xmlFeedEncoder struct {
+	encoder *xml.Encoder
+	root xml.StartElement
+
+	// Creates element of passed building.
+	newElement func(building *domain.Building) any
+}
+
+func (encoder *xmlFeedEncoder) Encode(building *domain.Building) error {
+	if err := encoder.encoder.Encode(encoder.newElement(building)); err != nil {
+		return fmt.Errorf("failed to encode building %d: %v", building.Id, err)
+	}
+	return nil
+}
+
+func (encoder *xmlFeedEncoder) Close() error {
+	if err := encoder.encoder.EncodeToken(encoder.root.End()); err != nil {
+		return fmt.Errorf("failed to encode end of the feed: %v", err)
+	}
+	return encoder.encoder.Close()
+}
+
+// Checks whether If-None-Match header value contains passed entity tag. Tags
+// are compared weakly, as required for the header.
+func matchesETag(header string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// Creates a new encoder of entity tag of the feed with passed format name.
+func newFeedETagEncoder(name string) *feedETagEncoder {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n", name)
+	return &feedETagEncoder{hash: hash}
+}
+
+// Writes XML header, start of passed root element and passed elements, which
+// precede buildings, and creates encoder of the buildings or returns an error.
+func newXMLFeedEncoder(
+		w io.Writer,
+		root xml.StartElement,
+		newElement func(building *domain.Building) any,
+		elements ...any) (*xmlFeedEncoder, error) {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, fmt.Errorf("failed to write XML header: %v", err)
+	}
+
+	// Try to encode beginning of the feed.
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.EncodeToken(root); err != nil {
+		return nil, fmt.Errorf("failed to encode start of the feed: %v", err)
+	}
+	for _, element := range elements {
+		if err := encoder.Encode(element); err != nil {
+			return nil, fmt.Errorf("failed to encode feed element: %v", err)
+		}
+	}
+
+	return &xmlFeedEncoder{
+		encoder: encoder,
+		root: root,
+		newElement: newElement,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package ginapi
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+)
+
+// Feed format in the structure of the Avito autoload. Each building is an ad of
+// a flat in a new building.
+//
+// Catalog stores only complexes, so the feed is not a complete Avito autoload:
+// elements the portal requires for ads, such as price and contacts, are not
+// rendered and must be added by the partner.
+type avitoFeedFormat struct{}
+
+type avitoFeedAd struct {
+	XMLName xml.Name     `xml:"Ad"`
+	Id string            `xml:"Id"`
+	Category string      `xml:"Category"`
+	OperationType string `xml:"OperationType"`
+	MarketType string    `xml:"MarketType"`
+	Address string       `xml:"Address"`
+	Description string   `xml:"Description"`
+	Floors uint64        `xml:"Floors"`
+	BuiltYear uint64     `xml:"BuiltYear"`
+}
+
+func (avitoFeedFormat) NewEncoder(
+		w io.Writer, generatedAt time.Time) (buildingEncoder, error) {
+	root := xml.StartElement{
+		Name: xml.Name{Local: "Ads"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "formatVersion"}, Value: "3"},
+			{Name: xml.Name{Local: "target"}, Value: "Avito.ru"},
+		},
+	}
+	return newXMLFeedEncoder(w, root, newAvitoFeedAd)
+}
+
+// Gets full address of passed building in the ad. Catalog does not store
+// streets, so the building is located by its complex name within the city.
+func getAvitoFeedAddress(building *domain.Building) string {
+	return strings.Join(
+		[]string{"Россия", building.Info.City, building.Info.Name}, ", ")
+}
+
+// Creates ad of the feed from passed building.
+func newAvitoFeedAd(building *domain.Building) any {
+	return &avitoFeedAd{
+		Id: strconv.FormatInt(building.Id, 10),
+		Category: "Квартиры",
+		OperationType: "Продам",
+		MarketType: "Новостройка",
+		Address: getAvitoFeedAddress(building),
+		Description: building.Info.Name,
+		Floors: building.Info.FloorsCount,
+		BuiltYear: building.Info.HandoverYear,
+	}
+}
@@ -0,0 +1,147 @@
+package ginapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Result of the feed import JSON view to make responses.
+type FeedImportView struct {
+	Inserted uint64  `json:"inserted"`
+	Updated uint64   `json:"updated"`
+	Skipped []string `json:"skipped"`
+}
+
+// Controller to handle feed routes.
+type FeedController struct {
+	ctx context.Context
+	service logic.BuildingService
+}
+
+// Get godoc
+//
+// @Summary     Gets a feed of buildings
+// @Description Streams all buildings as an XML feed in the structure of passed real-estate portal. Catalog stores only complexes, so elements the portals require for offers, such as price, creation date and sales agent, are not rendered and must be added by partners. Feed is rendered entirely before it is sent, so its entity tag matches the sent buildings, and it is not downloaded again while the tag matches.
+// @ID          get-feed
+// @Tags        feed
+// @Produce     xml
+// @Param       format                                    path     string true  "portal format, e.g. yandex.xml" Enums(yandex.xml, avito.xml)
+// @Param       If-None-Match                             header   string false "entity tag of the previously downloaded feed"
+// @Success     200                                       {file}   file
+// @Success     304                                       "feed is not changed"
+// @Header      200                                       {string} ETag "entity tag of the feed"
+// @Failure     404                                       {object} Problem
+// @Failure     500                                       {object} Problem
+// @Failure     503                                       {object} Problem
+// @Router      /feeds/{format}                           [get]
+func (controller *FeedController) Get(c *gin.Context) {
+	// Try to find format of the feed by its path.
+	name, found := strings.CutSuffix(c.Param("format"), ".xml")
+	format, ok := feedFormats[name]
+	if !found || !ok {
+		NewProblem(http.StatusNotFound, "unknown feed format").Push(c)
+		return
+	}
+
+	// Render feed to the buffer and compute entity tag of its buildings in the
+	// same pass, so the tag describes the sent buildings even if they are
+	// changed concurrently.
+	var body bytes.Buffer
+	etagEncoder := newFeedETagEncoder(name)
+	encoder, err := format.NewEncoder(&body, time.Now())
+	if err == nil {
+		filters := logic.NewBuildingFilters(nil, nil, nil)
+		err = controller.service.GetEach(
+			controller.ctx, filters, func(building *domain.Building) error {
+				if err := etagEncoder.Encode(building); err != nil {
+					return err
+				}
+				return encoder.Encode(building)
+			})
+		if err == nil {
+			err = encoder.Close()
+		}
+	}
+	if err != nil {
+		pushServiceError(c, err)
+		return
+	}
+
+	// Do not send feed if client already has it.
+	etag := etagEncoder.ETag()
+	c.Header("ETag", etag)
+	if matchesETag(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	// Make response with rendered feed.
+	c.Data(http.StatusOK, feedContentType, body.Bytes())
+}
+
+// Import godoc
+//
+// @Summary     Imports a partner feed
+// @Description Imports complexes of Yandex.Realty style feed as buildings. Complexes are identified by "yandex-building-id" or by their names and cities, so reimport of the feed updates buildings instead of duplicating them. Complexes without required information are skipped.
+// @ID          import-feed
+// @Tags        feed
+// @Accept      xml
+// @Produce     json
+// @Param       feed                                      body     string true  "Yandex.Realty style feed"
+// @Param       source                                    query    string true  "name of the partner which supplied the feed"
+// @Param       Idempotency-Key                           header   string false "key to identify retries of the same request"
+// @Success     200                                       {object} FeedImportView
+// @Failure     400                                       {object} Problem
+// @Failure     409                                       {object} Problem
+// @Failure     413                                       {object} Problem
+// @Failure     500                                       {object} Problem
+// @Failure     503                                       {object} Problem
+// @Router      /feeds/import                             [post]
+func (controller *FeedController) Import(c *gin.Context) {
+	// Try to extract source of the feed from context.
+	source := strings.TrimSpace(c.Query("source"))
+	if source == "" {
+		NewProblem(http.StatusBadRequest, "query source is required").Push(c)
+		return
+	}
+
+	// Use service to import the feed.
+	result, err := controller.service.ImportFeed(
+		controller.ctx, source, c.Request.Body)
+	if err != nil {
+		pushServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getFeedImportView(result))
+}
+
+// Creates a new feed controller.
+func NewFeedController(
+		ctx context.Context, service logic.BuildingService) *FeedController {
+	return &FeedController{
+		ctx: ctx,
+		service: service,
+	}
+}
+
+// Converts feed import result to its JSON view.
+func getFeedImportView(result *logic.FeedImportResult) *FeedImportView {
+	skipped := result.Skipped
+	if skipped == nil {
+		skipped = []string{}
+	}
+
+	return &FeedImportView{
+		Inserted: result.Inserted,
+		Updated: result.Updated,
+		Skipped: skipped,
+	}
+}
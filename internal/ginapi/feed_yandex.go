@@ -0,0 +1,82 @@
+package ginapi
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+)
+
+// Namespace of the Yandex.Realty feed.
+const yandexFeedNamespace =
+	"http://webmaster.yandex.ru/schemas/feed/realty/2010-06"
+
+// Feed format in the structure of the Yandex.Realty feed. Each building is an
+// offer of a flat in a new building.
+//
+// Feed has generation date and each offer has type, property type, category
+// and location with country and city. Catalog stores only complexes, so the
+// feed is not a complete Yandex.Realty feed. Other elements the portal
+// requires for offers are not rendered and must be added by the partner:
+//   - creation-date, because catalog does not store when buildings are added,
+//     and the generation date would change it on each request;
+//   - location/address, because catalog does not store streets;
+//   - price, area and sales-agent, because they describe flats and sellers
+//     instead of complexes.
+type yandexFeedFormat struct{}
+
+type yandexFeedGenerationDate struct {
+	XMLName xml.Name `xml:"generation-date"`
+	Date string      `xml:",chardata"`
+}
+
+type yandexFeedOffer struct {
+	XMLName xml.Name                 `xml:"offer"`
+	InternalId int64                 `xml:"internal-id,attr"`
+	Type string                      `xml:"type"`
+	PropertyType string              `xml:"property-type"`
+	Category string                  `xml:"category"`
+	Location yandexFeedOfferLocation `xml:"location"`
+	NewFlat string                   `xml:"new-flat"`
+	BuildingName string              `xml:"building-name"`
+	BuiltYear uint64                 `xml:"built-year"`
+	FloorsTotal uint64               `xml:"floors-total"`
+}
+
+type yandexFeedOfferLocation struct {
+	Country string      `xml:"country"`
+	LocalityName string `xml:"locality-name"`
+}
+
+func (yandexFeedFormat) NewEncoder(
+		w io.Writer, generatedAt time.Time) (buildingEncoder, error) {
+	root := xml.StartElement{
+		Name: xml.Name{Local: "realty-feed"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "xmlns"}, Value: yandexFeedNamespace},
+		},
+	}
+	generationDate := &yandexFeedGenerationDate{
+		Date: generatedAt.Format(time.RFC3339),
+	}
+	return newXMLFeedEncoder(w, root, newYandexFeedOffer, generationDate)
+}
+
+// Creates offer of the feed from passed building.
+func newYandexFeedOffer(building *domain.Building) any {
+	return &yandexFeedOffer{
+		InternalId: building.Id,
+		Type: "продажа",
+		PropertyType: "жилая",
+		Category: "квартира",
+		Location: yandexFeedOfferLocation{
+			Country: "Россия",
+			LocalityName: building.Info.City,
+		},
+		NewFlat: "да",
+		BuildingName: building.Info.Name,
+		BuiltYear: building.Info.HandoverYear,
+		FloorsTotal: building.Info.FloorsCount,
+	}
+}
@@ -4,6 +4,7 @@ go 1.22.5
 
 require (
 	github.com/gin-gonic/gin v1.10.0
+	github.com/go-playground/validator/v10 v10.20.0
 	github.com/rylenko/leadgen-market-task/internal/domain v0.0.0-20241016061444-911dacdffed6
 	github.com/rylenko/leadgen-market-task/internal/logic v0.0.0-20241016094056-4c5005fbc2cb
 	github.com/swaggo/files v1.0.1
@@ -27,7 +28,6 @@ require (
 	github.com/go-openapi/swag v0.19.15 // indirect
 	github.com/go-playground/locales v0.14.1 // indirect
 	github.com/go-playground/universal-translator v0.18.1 // indirect
-	github.com/go-playground/validator/v10 v10.20.0 // indirect
 	github.com/goccy/go-json v0.10.2 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
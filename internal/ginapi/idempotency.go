@@ -0,0 +1,152 @@
+package ginapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+const (
+	// Header with client generated key, which identifies retries of the same
+	// request.
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// Header which is set if response is returned again for a retry.
+	idempotentReplayedHeader = "Idempotent-Replayed"
+
+	// Maximum allowed length of the idempotency key.
+	maxIdempotencyKeyLength = 255
+
+	// Maximum allowed size of the request body with idempotency key, which is
+	// held in memory to hash it.
+	maxIdempotentBodySize = 1 << 20
+
+	// Maximum allowed size of the imported file with idempotency key. Files are
+	// larger than other bodies, so they have their own limit.
+	maxIdempotentImportSize = 32 << 20
+)
+
+// Response writer which records written body, so it can be stored.
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (recorder *idempotencyRecorder) Write(data []byte) (int, error) {
+	recorder.body.Write(data)
+	return recorder.ResponseWriter.Write(data)
+}
+
+func (recorder *idempotencyRecorder) WriteString(str string) (int, error) {
+	recorder.body.WriteString(str)
+	return recorder.ResponseWriter.WriteString(str)
+}
+
+// Creates middleware which honors idempotency key header. The first response
+// to the request with the key is stored and returned again for retries
+// instead of handling them. Responses with server errors are not stored, so
+// the request can be retried.
+//
+// Request body is read entirely to hash it, so body of the request with the
+// key is limited by passed size even for streaming endpoints.
+func newIdempotencyMiddleware(
+		ctx context.Context,
+		service logic.IdempotencyService,
+		maxBodySize int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Skip requests without idempotency key.
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			message := fmt.Sprintf(
+				"%s must be at most %d bytes long",
+				idempotencyKeyHeader,
+				maxIdempotencyKeyLength)
+			NewProblem(http.StatusBadRequest, message).Push(c)
+			c.Abort()
+			return
+		}
+
+		// Try to hash the request.
+		hash, err := hashRequest(c, maxBodySize)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			message := fmt.Sprintf(
+				"request body with %s must be at most %d bytes long",
+				idempotencyKeyHeader,
+				maxBytesErr.Limit)
+			NewProblem(http.StatusRequestEntityTooLarge, message).Push(c)
+			c.Abort()
+			return
+		} else if err != nil {
+			NewProblem(http.StatusBadRequest, err.Error()).Push(c)
+			c.Abort()
+			return
+		}
+
+		// Try to reserve the key or get the stored response.
+		response, err := service.Begin(ctx, key, hash)
+		if err != nil {
+			pushServiceError(c, err)
+			c.Abort()
+			return
+		}
+
+		// Return the stored response for a retry.
+		if response != nil {
+			c.Header(idempotentReplayedHeader, "true")
+			c.Data(response.Status, response.ContentType, response.Body)
+			c.Abort()
+			return
+		}
+
+		// Handle the request and record its response.
+		recorder := &idempotencyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// Store the response or release the key if the request can be retried.
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			err = service.Release(ctx, key)
+		} else {
+			response := logic.NewIdempotentResponse(
+				status,
+				recorder.Header().Get("Content-Type"),
+				recorder.body.Bytes())
+			err = service.Complete(ctx, key, response)
+		}
+		if err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+// Hashes method, URI and body of the request. Body is restored, so it can be
+// read by the handler. *http.MaxBytesError is returned if body is larger than
+// passed size.
+func hashRequest(c *gin.Context, maxBodySize int64) (string, error) {
+	// Try to read and restore body.
+	reader := http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize)
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read request body: %w", err)
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", c.Request.Method, c.Request.URL.RequestURI())
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -33,19 +33,24 @@ import (
 func Launch(
 		ctx context.Context,
 		buildingService logic.BuildingService,
+		idempotencyService logic.IdempotencyService,
 		addr ...string) error {
 	// Initialize services.
 	if err := buildingService.Init(ctx); err != nil {
-		fmt.Errorf("failed to initialize building service: %v", err)
+		return fmt.Errorf("failed to initialize building service: %w", err)
+	}
+	if err := idempotencyService.Init(ctx); err != nil {
+		return fmt.Errorf("failed to initialize idempotency service: %w", err)
 	}
 
 	// Create, fill engine with middlewares and handlers and run it.
 	engine := gin.Default()
-	// addMiddlewares(engine)
+	addMiddlewares(engine)
 
 	// Add v1 API controllers.
 	v1group := engine.Group("/api/v1")
-	addBuildingController(v1group, ctx, buildingService)
+	addBuildingController(v1group, ctx, buildingService, idempotencyService)
+	addFeedController(v1group, ctx, buildingService, idempotencyService)
 
 	// Add swagger controller.
 	addSwaggerController(engine)
@@ -56,26 +61,67 @@ func Launch(
 	return engine.Run(addr...)
 }
 
-// Registers building handlers to the passed group.
+// Registers building handlers to the passed group. Handlers which change
+// buildings honor idempotency keys.
 func addBuildingController(
 		group *gin.RouterGroup,
 		ctx context.Context,
-		service logic.BuildingService) {
-	// Create a new instance of the controller.
+		service logic.BuildingService,
+		idempotencyService logic.IdempotencyService) {
+	// Create a new instance of the controller and idempotency middlewares.
 	controller := NewBuildingController(ctx, service)
+	idempotent := newIdempotencyMiddleware(
+		ctx, idempotencyService, maxIdempotentBodySize)
+	idempotentImport := newIdempotencyMiddleware(
+		ctx, idempotencyService, maxIdempotentImportSize)
 
 	// Create buildings sub-group and add controller handlers to it.
 	buildings := group.Group("/buildings")
 	{
 		buildings.GET("", controller.GetAll)
-		buildings.POST("", controller.Create)
+		buildings.POST("", idempotent, controller.Create)
+		buildings.POST("/batch", idempotent, controller.CreateBatch)
+		buildings.PUT(
+			"/by-external/:source/:id", idempotent, controller.Upsert)
+		buildings.GET("/duplicates", controller.FindDuplicates)
+		buildings.GET("/export", controller.Export)
+		buildings.POST("/import", idempotentImport, controller.Import)
+		buildings.GET("/page", controller.GetPage)
+		buildings.GET("/stats", controller.GetStats)
+		buildings.GET("/suggest", controller.Suggest)
+		buildings.GET("/:id", controller.Get)
+		buildings.PUT("/:id", idempotent, controller.Update)
+		buildings.PATCH("/:id", idempotent, controller.Patch)
+		buildings.DELETE("/:id", idempotent, controller.Delete)
+		buildings.POST("/:id/merge", idempotent, controller.Merge)
+	}
+}
+
+// Registers feed handlers to the passed group. Handler which imports feeds
+// honors idempotency keys.
+func addFeedController(
+		group *gin.RouterGroup,
+		ctx context.Context,
+		service logic.BuildingService,
+		idempotencyService logic.IdempotencyService) {
+	// Create a new instance of the controller and idempotency middleware.
+	controller := NewFeedController(ctx, service)
+	idempotentImport := newIdempotencyMiddleware(
+		ctx, idempotencyService, maxIdempotentImportSize)
+
+	// Create feeds sub-group and add controller handlers to it.
+	feeds := group.Group("/feeds")
+	{
+		feeds.POST("/import", idempotentImport, controller.Import)
+		feeds.GET("/:format", controller.Get)
 	}
 }
 
 // Adds all middlewares to the passed engine.
-// func addMiddlewares(engine *gin.Engine) {
+func addMiddlewares(engine *gin.Engine) {
+	engine.Use(correlationIdMiddleware)
 	// engine.Use(printErrorsMiddleware)
-// }
+}
 
 // Adds swagger controller to the engine.
 func addSwaggerController(engine *gin.Engine) {
@@ -0,0 +1,81 @@
+package ginapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+)
+
+const (
+	// Content type of the problem details according to RFC 7807.
+	problemContentType = "application/problem+json"
+
+	// Type of the problems which have no additional semantics beyond the
+	// status code.
+	defaultProblemType = "about:blank"
+
+	// Prefix of the problem types, which is followed by a machine-readable
+	// code, for example "urn:problem:building_not_found".
+	problemTypePrefix = "urn:problem:"
+)
+
+// Handlers problem details according to RFC 7807. Type identifies the problem
+// for machines, while title and detail describe it for humans. Instance is the
+// URI of the request and correlation identifier matches the X-Request-Id
+// header of the response. Errors of the fields are set if request does not
+// satisfy domain rules.
+type Problem struct {
+	Type string              `json:"type"`
+	Title string             `json:"title"`
+	Status int               `json:"status"`
+	Detail string            `json:"detail,omitempty"`
+	Instance string          `json:"instance,omitempty"`
+	CorrelationId string     `json:"correlation_id,omitempty"`
+	Errors []*FieldErrorView `json:"errors,omitempty"`
+}
+
+// Error of a single field JSON view to make responses.
+type FieldErrorView struct {
+	Field string   `json:"field"`
+	Message string `json:"message"`
+}
+
+// Pushes problem's JSON to the passed context with problem content type. Sets
+// instance and correlation identifier of the request.
+func (p *Problem) Push(c *gin.Context) {
+	p.Instance = c.Request.URL.RequestURI()
+	p.CorrelationId = getCorrelationId(c)
+
+	c.Header("Content-Type", problemContentType)
+	c.JSON(p.Status, p)
+}
+
+// Sets type of the problem using passed machine-readable code.
+func (p *Problem) WithType(code string) *Problem {
+	p.Type = problemTypePrefix + code
+	return p
+}
+
+// Sets errors of passed fields.
+func (p *Problem) WithFieldErrors(fieldErrs []*domain.FieldError) *Problem {
+	p.Errors = make([]*FieldErrorView, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		p.Errors = append(p.Errors, &FieldErrorView{
+			Field: fieldErr.Field,
+			Message: fieldErr.Message,
+		})
+	}
+	return p
+}
+
+// Creates a new problem with passed status and detail. Title is the status
+// text and type is "about:blank" until it is replaced.
+func NewProblem(status int, detail string) *Problem {
+	return &Problem{
+		Type: defaultProblemType,
+		Title: http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
@@ -0,0 +1,143 @@
+package ginapi
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Static parts of the XLSX workbook with a single worksheet.
+var xlsxStaticParts = []struct {
+	name string
+	content string
+}{
+	{
+		"[Content_Types].xml",
+		xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		"_rels/.rels",
+		xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		"xl/workbook.xml",
+		xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		"xl/_rels/workbook.xml.rels",
+		xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+const (
+	// Name of the worksheet part, which is written last.
+	xlsxSheetName = "xl/worksheets/sheet1.xml"
+
+	// Beginning of the worksheet part before rows.
+	xlsxSheetHeader = xml.Header +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetData>`
+
+	// End of the worksheet part after rows.
+	xlsxSheetFooter = `</sheetData></worksheet>`
+)
+
+// Writer of the XLSX workbook with a single worksheet. Rows are written to the
+// underlying writer as they are passed, so the whole workbook is never held in
+// memory.
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet *bufio.Writer
+}
+
+// Writes a row to the worksheet. Cells must be strings or integers.
+func (writer *xlsxWriter) WriteRow(cells ...any) error {
+	if _, err := writer.sheet.WriteString("<row>"); err != nil {
+		return err
+	}
+
+	for _, cell := range cells {
+		if err := writer.writeCell(cell); err != nil {
+			return err
+		}
+	}
+
+	_, err := writer.sheet.WriteString("</row>")
+	return err
+}
+
+// Writes the end of the worksheet and closes the archive. Underlying writer is
+// not closed.
+func (writer *xlsxWriter) Close() error {
+	if _, err := writer.sheet.WriteString(xlsxSheetFooter); err != nil {
+		return err
+	}
+	if err := writer.sheet.Flush(); err != nil {
+		return err
+	}
+	return writer.archive.Close()
+}
+
+// Writes a single cell. Strings are written inline, so the workbook does not
+// need shared strings table.
+func (writer *xlsxWriter) writeCell(cell any) error {
+	switch value := cell.(type) {
+	case string:
+		if _, err := writer.sheet.WriteString(
+				`<c t="inlineStr"><is><t xml:space="preserve">`); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(writer.sheet, []byte(value)); err != nil {
+			return err
+		}
+		_, err := writer.sheet.WriteString("</t></is></c>")
+		return err
+	case int64, uint64:
+		_, err := fmt.Fprintf(writer.sheet, "<c><v>%d</v></c>", value)
+		return err
+	default:
+		return fmt.Errorf("unsupported XLSX cell type %T", cell)
+	}
+}
+
+// Creates a new XLSX writer, which writes static workbook parts and the
+// beginning of the worksheet to passed writer.
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+
+	// Write static parts of the workbook.
+	for _, part := range xlsxStaticParts {
+		partWriter, err := archive.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %v", part.name, err)
+		}
+		if _, err := io.WriteString(partWriter, part.content); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v", part.name, err)
+		}
+	}
+
+	// Start worksheet part, which stays open until the writer is closed.
+	sheetWriter, err := archive.Create(xlsxSheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", xlsxSheetName, err)
+	}
+	sheet := bufio.NewWriter(sheetWriter)
+	if _, err := sheet.WriteString(xlsxSheetHeader); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %v", xlsxSheetName, err)
+	}
+
+	return &xlsxWriter{archive: archive, sheet: sheet}, nil
+}
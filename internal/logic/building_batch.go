@@ -0,0 +1,29 @@
+package logic
+
+import (
+	"errors"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+)
+
+// ErrBuildingBatchAborted is returned for buildings that are not created,
+// because another building of the atomic batch failed.
+var ErrBuildingBatchAborted = errors.New(
+	"building batch is aborted because of another building")
+
+// BuildingBatchResult is a result of creating a single building of the batch.
+//
+// If creation is failed, building is nil and error is set.
+type BuildingBatchResult struct {
+	Building *domain.Building
+	Err error
+}
+
+// NewBuildingBatchResult creates a new instance of batch result.
+func NewBuildingBatchResult(
+		building *domain.Building, err error) *BuildingBatchResult {
+	return &BuildingBatchResult{
+		Building: building,
+		Err: err,
+	}
+}
@@ -0,0 +1,68 @@
+package logic
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+)
+
+// ErrInvalidBuildingCursor is returned when passed cursor can not be decoded.
+var ErrInvalidBuildingCursor = NewError(
+		ErrInvalidArgument, "invalid_building_cursor", "invalid building cursor")
+
+// BuildingCursor points to the last building of a page. Next page consists of
+// buildings that go after it according to the sort keys.
+//
+// Cursor keeps values of all sortable fields of the building. It is passed to
+// the clients as opaque string, so its fields may change without breaking
+// them.
+type BuildingCursor struct {
+	Id int64            `json:"id"`
+	Name string         `json:"name"`
+	City string         `json:"city"`
+	HandoverYear uint64 `json:"handover_year"`
+	FloorsCount uint64  `json:"floors_count"`
+}
+
+// Encode encodes cursor to the opaque URL-safe string.
+func (cursor *BuildingCursor) Encode() string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Value gets value of passed field of the building the cursor points to.
+func (cursor *BuildingCursor) Value(field BuildingField) any {
+	info := domain.NewBuildingInfo(
+		cursor.Name, cursor.City, cursor.HandoverYear, cursor.FloorsCount)
+	return GetBuildingFieldValue(domain.NewBuilding(cursor.Id, info), field)
+}
+
+// DecodeBuildingCursor decodes cursor from the opaque string or returns
+// ErrInvalidBuildingCursor.
+func DecodeBuildingCursor(str string) (*BuildingCursor, error) {
+	// Try to decode base64 string.
+	data, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return nil, ErrInvalidBuildingCursor
+	}
+
+	// Try to decode cursor fields.
+	var cursor BuildingCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidBuildingCursor
+	}
+
+	return &cursor, nil
+}
+
+// NewBuildingCursor creates a new cursor that points to passed building.
+func NewBuildingCursor(building *domain.Building) *BuildingCursor {
+	return &BuildingCursor{
+		Id: building.Id,
+		Name: building.Info.Name,
+		City: building.Info.City,
+		HandoverYear: building.Info.HandoverYear,
+		FloorsCount: building.Info.FloorsCount,
+	}
+}
@@ -0,0 +1,252 @@
+package logic
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+)
+
+// ErrSelfMerge is returned if a building is merged into itself.
+var ErrSelfMerge = NewError(
+		ErrInvalidArgument,
+		"self_merge",
+		"building can not be merged into itself")
+
+// Words of building names which do not distinguish buildings, such as type of
+// the complex.
+var buildingNameStopWords = []string{"жк", "жилой", "комплекс", "дом"}
+
+// DuplicateCriteria describes how close buildings of the same city and
+// handover year must be to be considered duplicates.
+type DuplicateCriteria struct {
+	// Minimum similarity of normalized names from 0 to 1.
+	MinSimilarity float64
+
+	// Maximum difference of floors counts.
+	MaxFloorsDifference uint64
+}
+
+// NewDuplicateCriteria creates a new instance of duplicate criteria.
+func NewDuplicateCriteria(
+		minSimilarity float64, maxFloorsDifference uint64) *DuplicateCriteria {
+	return &DuplicateCriteria{
+		MinSimilarity: minSimilarity,
+		MaxFloorsDifference: maxFloorsDifference,
+	}
+}
+
+// BuildingDuplicate is a pair of likely duplicate buildings. Building is the
+// older one, so it is suggested as canonical.
+type BuildingDuplicate struct {
+	Building *domain.Building
+	Duplicate *domain.Building
+
+	// Similarity of normalized names from 0 to 1.
+	Similarity float64
+}
+
+// Finder of the buildings of a group which may be duplicates of a building.
+//
+// If names must be similar, candidates are buildings with common trigrams of
+// names, because names without them have zero similarity. Otherwise, names
+// are not compared, so candidates are buildings with close floors counts.
+type duplicateCandidates struct {
+	group []*domain.Building
+	trigrams []map[string]struct{}
+	criteria *DuplicateCriteria
+
+	// Indexes of the buildings by trigrams of their names. Buildings, names of
+	// which have no trigrams, are indexed by empty string, so their equal names
+	// are still compared. Nil if names are not compared.
+	byTrigram map[string][]int
+
+	// Indexes of the buildings sorted by floors counts. Nil if names are
+	// compared.
+	byFloors []int
+}
+
+// Returns sorted indexes of the buildings after passed one, which may be its
+// duplicates.
+func (candidates *duplicateCandidates) find(i int) []int {
+	found := make(map[int]struct{})
+	if candidates.byTrigram != nil {
+		// Collect buildings with common trigrams.
+		for _, trigram := range getTrigramKeys(candidates.trigrams[i]) {
+			for _, j := range candidates.byTrigram[trigram] {
+				if j > i {
+					found[j] = struct{}{}
+				}
+			}
+		}
+	} else {
+		// Collect buildings with floors counts in the window around the
+		// building, starting from the first one which fits it.
+		floorsCount := candidates.group[i].Info.FloorsCount
+		difference := candidates.criteria.MaxFloorsDifference
+		lower := floorsCount - min(floorsCount, difference)
+		start, _ := slices.BinarySearchFunc(
+			candidates.byFloors, lower, func(j int, target uint64) int {
+				return cmp.Compare(candidates.group[j].Info.FloorsCount, target)
+			})
+		for _, j := range candidates.byFloors[start:] {
+			other := candidates.group[j].Info.FloorsCount
+			if other > floorsCount && other - floorsCount > difference {
+				break
+			}
+			if j > i {
+				found[j] = struct{}{}
+			}
+		}
+	}
+
+	indexes := make([]int, 0, len(found))
+	for j := range found {
+		indexes = append(indexes, j)
+	}
+	slices.Sort(indexes)
+	return indexes
+}
+
+// NormalizeBuildingName converts building name to the form which does not
+// depend on case, punctuation, words order and words like "ЖК". For example,
+// "ЖК Солнечный" and "Солнечный ЖК" have the same normalized name.
+func NormalizeBuildingName(name string) string {
+	// Split lowercase name to words of letters and digits.
+	name = strings.ReplaceAll(strings.ToLower(name), "ё", "е")
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	// Remove words which do not distinguish buildings, unless there are no
+	// other words.
+	significant := slices.DeleteFunc(slices.Clone(words), func(word string) bool {
+		return slices.Contains(buildingNameStopWords, word)
+	})
+	if len(significant) > 0 {
+		words = significant
+	}
+
+	slices.Sort(words)
+	return strings.Join(words, " ")
+}
+
+// Computes similarity of normalized names from 0 to 1 as a share of common
+// trigrams of their words. Trigrams of the names are passed, so they are not
+// got again for each pair.
+func computeNameSimilarity(
+		a, b string, aTrigrams, bTrigrams map[string]struct{}) float64 {
+	if a == b {
+		return 1
+	}
+	if len(aTrigrams) == 0 || len(bTrigrams) == 0 {
+		return 0
+	}
+
+	common := 0
+	for trigram := range aTrigrams {
+		if _, ok := bTrigrams[trigram]; ok {
+			common++
+		}
+	}
+	return float64(common) /
+		float64(len(aTrigrams) + len(bTrigrams) - common)
+}
+
+// Returns trigrams of the name, which are keys of the buildings index. Names
+// without trigrams have a single empty key.
+func getTrigramKeys(trigrams map[string]struct{}) []string {
+	if len(trigrams) == 0 {
+		return []string{""}
+	}
+	keys := make([]string, 0, len(trigrams))
+	for trigram := range trigrams {
+		keys = append(keys, trigram)
+	}
+	return keys
+}
+
+// Finds at most limit duplicates among buildings of the same city and handover
+// year. Each building is compared only with its candidates, so buildings with
+// different names or far floors counts are not compared at all.
+func findDuplicatesInGroup(
+		group []*domain.Building,
+		criteria *DuplicateCriteria,
+		limit uint64) []*BuildingDuplicate {
+	// Normalize names and get their trigrams once for all pairs.
+	names := make([]string, len(group))
+	trigrams := make([]map[string]struct{}, len(group))
+	for i, building := range group {
+		names[i] = NormalizeBuildingName(building.Info.Name)
+		trigrams[i] = GetTrigrams(names[i])
+	}
+	candidates := newDuplicateCandidates(group, trigrams, criteria)
+
+	var duplicates []*BuildingDuplicate
+	for i, building := range group {
+		for _, j := range candidates.find(i) {
+			other := group[j]
+
+			// Check floors counts difference.
+			difference := max(building.Info.FloorsCount, other.Info.FloorsCount) -
+				min(building.Info.FloorsCount, other.Info.FloorsCount)
+			if difference > criteria.MaxFloorsDifference {
+				continue
+			}
+
+			// Check names similarity.
+			similarity := computeNameSimilarity(
+				names[i], names[j], trigrams[i], trigrams[j])
+			if similarity < criteria.MinSimilarity {
+				continue
+			}
+
+			duplicates = append(duplicates, &BuildingDuplicate{
+				Building: building,
+				Duplicate: other,
+				Similarity: similarity,
+			})
+			if uint64(len(duplicates)) >= limit {
+				return duplicates
+			}
+		}
+	}
+	return duplicates
+}
+
+// Creates a new finder of duplicate candidates among buildings of the group
+// with passed trigrams of their normalized names.
+func newDuplicateCandidates(
+		group []*domain.Building,
+		trigrams []map[string]struct{},
+		criteria *DuplicateCriteria) *duplicateCandidates {
+	candidates := &duplicateCandidates{
+		group: group,
+		trigrams: trigrams,
+		criteria: criteria,
+	}
+
+	// Index buildings by trigrams if names must be similar.
+	if criteria.MinSimilarity > 0 {
+		candidates.byTrigram = make(map[string][]int)
+		for i := range group {
+			for _, trigram := range getTrigramKeys(trigrams[i]) {
+				candidates.byTrigram[trigram] = append(
+					candidates.byTrigram[trigram], i)
+			}
+		}
+		return candidates
+	}
+
+	// Otherwise, sort buildings by floors counts.
+	candidates.byFloors = make([]int, len(group))
+	for i := range group {
+		candidates.byFloors[i] = i
+	}
+	slices.SortStableFunc(candidates.byFloors, func(i, j int) int {
+		return cmp.Compare(group[i].Info.FloorsCount, group[j].Info.FloorsCount)
+	})
+	return candidates
+}
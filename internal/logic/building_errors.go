@@ -0,0 +1,6 @@
+package logic
+
+// ErrBuildingNotFound is returned when a building with the requested
+// identifier does not exist.
+var ErrBuildingNotFound = NewError(
+		ErrNotFound, "building_not_found", "building not found")
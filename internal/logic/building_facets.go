@@ -0,0 +1,28 @@
+package logic
+
+// ErrUnfacetableBuildingField is returned when facet is requested for a field
+// which values can not be counted.
+var ErrUnfacetableBuildingField = NewError(
+		ErrInvalidArgument,
+		"unfacetable_building_field",
+		"building field can not be used as facet")
+
+// FacetableBuildingFields contains building fields which can be used as
+// facets.
+var FacetableBuildingFields = []BuildingField{
+	BuildingFieldCity,
+	BuildingFieldHandoverYear,
+	BuildingFieldFloorsCount,
+}
+
+// BuildingFacets contains counts of buildings by distinct values of the
+// fields, ordered by count in descending order. Each facet is counted under
+// all filters except the filter on its own field, so the counts show how many
+// buildings would be selected if the value was chosen instead.
+//
+// Facets are slices. If one of them is nil, then the facet was not requested.
+type BuildingFacets struct {
+	City []*ValueCount[string]
+	HandoverYear []*ValueCount[uint64]
+	FloorsCount []*ValueCount[uint64]
+}
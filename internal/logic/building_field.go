@@ -0,0 +1,57 @@
+package logic
+
+import "github.com/rylenko/leadgen-market-task/internal/domain"
+
+// ErrUnknownBuildingField is returned when passed name does not match any
+// building field.
+var ErrUnknownBuildingField = NewError(
+		ErrInvalidArgument, "unknown_building_field", "unknown building field")
+
+// BuildingField is a name of building field, which can be used to sort, filter
+// or group buildings.
+type BuildingField string
+
+const (
+	BuildingFieldId BuildingField = "id"
+	BuildingFieldName BuildingField = "name"
+	BuildingFieldCity BuildingField = "city"
+	BuildingFieldHandoverYear BuildingField = "handover_year"
+	BuildingFieldFloorsCount BuildingField = "floors_count"
+)
+
+// BuildingFields contains all known building fields.
+var BuildingFields = []BuildingField{
+	BuildingFieldId,
+	BuildingFieldName,
+	BuildingFieldCity,
+	BuildingFieldHandoverYear,
+	BuildingFieldFloorsCount,
+}
+
+// ParseBuildingField parses building field from its name or returns
+// ErrUnknownBuildingField.
+func ParseBuildingField(name string) (BuildingField, error) {
+	for _, field := range BuildingFields {
+		if string(field) == name {
+			return field, nil
+		}
+	}
+	return "", ErrUnknownBuildingField
+}
+
+// GetBuildingFieldValue gets value of passed field from the building. Values
+// of text fields are strings, identifier is int64 and others are uint64.
+func GetBuildingFieldValue(building *domain.Building, field BuildingField) any {
+	switch field {
+	case BuildingFieldName:
+		return building.Info.Name
+	case BuildingFieldCity:
+		return building.Info.City
+	case BuildingFieldHandoverYear:
+		return building.Info.HandoverYear
+	case BuildingFieldFloorsCount:
+		return building.Info.FloorsCount
+	default:
+		return building.Id
+	}
+}
@@ -6,18 +6,72 @@ package logic
 // Filter values ​​are pointers. If one of them is nil, then the filter
 // parameter is not set.
 type BuildingFilters struct {
-	City *string
-	HandoverYear *uint64
-	FloorsCount *uint64
+	City *Filter[string]
+	HandoverYear *Filter[uint64]
+	FloorsCount *Filter[uint64]
+
+	// Text search parameters.
+	Search *BuildingSearch
+
+	// Sort keys in priority order. Buildings are additionally ordered by
+	// identifier, so the order is always stable. If search is set and there
+	// are no sort keys, buildings are ordered by relevance.
+	Sort []*BuildingSortKey
+
+	// Pagination parameters. After cursor selects buildings that go after it
+	// according to the sort keys.
+	Limit *uint64
+	Offset *uint64
+	After *BuildingCursor
 }
 
 // NewBuildingFilters creates a new instance of filter parameters
 // structure.
 func NewBuildingFilters(
-		city *string, handoverYear, floorsCount *uint64) *BuildingFilters {
+		city *Filter[string],
+		handoverYear, floorsCount *Filter[uint64]) *BuildingFilters {
 	return &BuildingFilters{
 		City: city,
 		HandoverYear: handoverYear,
 		FloorsCount: floorsCount,
 	}
 }
+
+// WithPagination sets pagination parameters of the filters and returns them.
+func (filters *BuildingFilters) WithPagination(
+		limit, offset *uint64, after *BuildingCursor) *BuildingFilters {
+	filters.Limit = limit
+	filters.Offset = offset
+	filters.After = after
+	return filters
+}
+
+// WithSearch sets text search parameters of the filters and returns them.
+func (filters *BuildingFilters) WithSearch(
+		search *BuildingSearch) *BuildingFilters {
+	filters.Search = search
+	return filters
+}
+
+// WithSort sets sort keys of the filters and returns them.
+func (filters *BuildingFilters) WithSort(
+		sort []*BuildingSortKey) *BuildingFilters {
+	filters.Sort = sort
+	return filters
+}
+
+// WithoutFilter returns a copy of the filters without the filter on passed
+// field.
+func (filters *BuildingFilters) WithoutFilter(
+		field BuildingField) *BuildingFilters {
+	copied := *filters
+	switch field {
+	case BuildingFieldCity:
+		copied.City = nil
+	case BuildingFieldHandoverYear:
+		copied.HandoverYear = nil
+	case BuildingFieldFloorsCount:
+		copied.FloorsCount = nil
+	}
+	return &copied
+}
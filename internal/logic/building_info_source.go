@@ -0,0 +1,17 @@
+package logic
+
+import "github.com/rylenko/leadgen-market-task/internal/domain"
+
+// BuildingInfoSource is a stream of building information, which is read one by
+// one, so the whole stream is never kept in memory.
+type BuildingInfoSource interface {
+	// Next must advance to the next building information and return true, or
+	// return false if there are no more of them or an error occurred.
+	Next() bool
+
+	// Info must return building information the source is advanced to.
+	Info() *domain.BuildingInfo
+
+	// Err must return an error occurred during iteration, if any.
+	Err() error
+}
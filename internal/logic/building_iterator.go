@@ -0,0 +1,21 @@
+package logic
+
+import "github.com/rylenko/leadgen-market-task/internal/domain"
+
+// BuildingIterator is a lazy stream of buildings, which are fetched one by one
+// while the iterator is open, so the whole result is never kept in memory.
+type BuildingIterator interface {
+	// Next must advance to the next building and return true, or return false
+	// if there are no more of them or an error occurred.
+	Next() bool
+
+	// Building must return a building the iterator is advanced to.
+	Building() *domain.Building
+
+	// Err must return an error occurred during iteration, if any.
+	Err() error
+
+	// Close must release resources of the iterator. It must be safe to call it
+	// more than once.
+	Close()
+}
@@ -0,0 +1,32 @@
+package logic
+
+// BuildingPatch contains building information fields that must be changed
+// during partial update.
+//
+// Patch values are pointers. If one of them is nil, then the field is left
+// unchanged.
+type BuildingPatch struct {
+	Name *string
+	City *string
+	HandoverYear *uint64
+	FloorsCount *uint64
+}
+
+// IsEmpty checks that patch does not change any field.
+func (patch *BuildingPatch) IsEmpty() bool {
+	return patch.Name == nil &&
+		patch.City == nil &&
+		patch.HandoverYear == nil &&
+		patch.FloorsCount == nil
+}
+
+// NewBuildingPatch creates a new instance of partial update structure.
+func NewBuildingPatch(
+		name, city *string, handoverYear, floorsCount *uint64) *BuildingPatch {
+	return &BuildingPatch{
+		Name: name,
+		City: city,
+		HandoverYear: handoverYear,
+		FloorsCount: floorsCount,
+	}
+}
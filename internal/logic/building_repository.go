@@ -8,16 +8,112 @@ import (
 
 // BuildingRepository is an interface that describes the required capabilities
 // of the building repository.
+//
+// Errors of the storage must be translated to the errors of ErrConflict,
+// ErrValidation or ErrUnavailable kinds where it is possible.
 type BuildingRepository interface {
+	// Count must count all buildings according to the passed filter parameters,
+	// ignoring pagination, or return an error.
+	Count(ctx context.Context, filters *BuildingFilters) (uint64, error)
+
+	// Delete must delete a building by its identifier or return an error. If
+	// building does not exist, ErrBuildingNotFound must be returned.
+	Delete(ctx context.Context, id int64) error
+
+	// Get must get a building by its identifier or return an error. If building
+	// does not exist, ErrBuildingNotFound must be returned.
+	Get(ctx context.Context, id int64) (*domain.Building, error)
+
 	// GetAll must get all buildings according to the passed filter parameters or
 	// return an error.
 	GetAll(
 		ctx context.Context, filters *BuildingFilters) ([]*domain.Building, error)
 
+	// GetFacets must count buildings by distinct values of each passed field
+	// according to the passed filter parameters, ignoring the filter on the
+	// field itself, sort keys and pagination, or return an error.
+	GetFacets(
+		ctx context.Context,
+		filters *BuildingFilters,
+		fields []BuildingField) (*BuildingFacets, error)
+
+	// GetStats must get aggregate statistics of the buildings according to the
+	// passed filter parameters, ignoring sort keys and pagination, or return an
+	// error.
+	GetStats(
+		ctx context.Context, filters *BuildingFilters) (*BuildingStats, error)
+
 	// Insert must insert a structure to the repository or return an error.
 	Insert(
 		ctx context.Context, info *domain.BuildingInfo) (*domain.Building, error)
 
+	// InsertBatch must insert passed buildings to the repository and return
+	// result of each of them in the same order or return an error if batch can
+	// not be processed at all. If batch is atomic, either all buildings are
+	// inserted or none of them, and buildings that are not inserted because of
+	// others get ErrBuildingBatchAborted.
+	InsertBatch(
+		ctx context.Context,
+		infos []*domain.BuildingInfo,
+		atomic bool) ([]*BuildingBatchResult, error)
+
+	// InsertStream must insert all buildings of passed source to the repository
+	// and return their count or return an error. Either all buildings are
+	// inserted or none of them.
+	InsertStream(
+		ctx context.Context, source BuildingInfoSource) (uint64, error)
+
 	// Init must initialize repository before queries.
 	Init(ctx context.Context) error
+
+	// Iterate must open an iterator over buildings according to the passed
+	// filter parameters or return an error. Buildings must be fetched lazily as
+	// the iterator is advanced. Iterator must be closed by the caller.
+	Iterate(
+		ctx context.Context, filters *BuildingFilters) (BuildingIterator, error)
+
+	// Merge must delete a duplicate building and move its external identifier
+	// to a building with passed identifier if the latter does not have one, and
+	// return the resulting building or return an error. If one of the buildings
+	// does not exist, ErrBuildingNotFound must be returned.
+	Merge(ctx context.Context, id, duplicateId int64) (*domain.Building, error)
+
+	// Patch must change only passed fields of a building with passed identifier
+	// and return updated building or an error. If building does not exist,
+	// ErrBuildingNotFound must be returned.
+	Patch(
+		ctx context.Context,
+		id int64,
+		patch *BuildingPatch) (*domain.Building, error)
+
+	// Suggest must get at most limit distinct values of passed field that start
+	// with passed prefix, ignoring case, or return an error. Values must be
+	// ordered by count of buildings that have them in descending order.
+	Suggest(
+		ctx context.Context,
+		field BuildingField,
+		prefix string,
+		limit uint64) ([]*BuildingSuggestion, error)
+
+	// Update must replace information of a building with passed identifier or
+	// return an error. If building does not exist, ErrBuildingNotFound must be
+	// returned.
+	Update(
+		ctx context.Context,
+		id int64,
+		info *domain.BuildingInfo) (*domain.Building, error)
+
+	// Upsert must insert passed building or update the one with the same
+	// external identifier and return it and whether it is inserted or return an
+	// error.
+	Upsert(
+		ctx context.Context,
+		info *ExternalBuildingInfo) (*domain.Building, bool, error)
+
+	// UpsertBatch must insert passed buildings or update the ones with the same
+	// external identifiers and return their counts or return an error. Either
+	// all buildings are upserted or none of them.
+	UpsertBatch(
+		ctx context.Context,
+		infos []*ExternalBuildingInfo) (*BuildingUpsertCounts, error)
 }
@@ -0,0 +1,42 @@
+package logic
+
+import (
+	"strings"
+	"unicode"
+)
+
+// BuildingSearch contains parameters of the text search over buildings. Search
+// tolerates typos and matches partial names.
+type BuildingSearch struct {
+	Query string
+
+	// Whether to search over city in addition to name.
+	IncludeCity bool
+}
+
+// NewBuildingSearch creates a new instance of search parameters.
+func NewBuildingSearch(query string, includeCity bool) *BuildingSearch {
+	return &BuildingSearch{
+		Query: query,
+		IncludeCity: includeCity,
+	}
+}
+
+// GetTrigrams gets set of trigrams of the text like pg_trgm extension does:
+// text is split to lowercase words of letters and digits and each word is
+// padded with two spaces before and one space after it, so short words still
+// have trigrams.
+func GetTrigrams(text string) map[string]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	trigrams := make(map[string]struct{})
+	for _, word := range words {
+		runes := []rune("  " + word + " ")
+		for i := 0; i + 3 <= len(runes); i++ {
+			trigrams[string(runes[i:i + 3])] = struct{}{}
+		}
+	}
+	return trigrams
+}
@@ -2,23 +2,135 @@ package logic
 
 import (
 	"context"
+	"io"
 
 	"github.com/rylenko/leadgen-market-task/internal/domain"
 )
 
 // BuildingService is an interface that describes the required capabilities of
 // the building service.
+//
+// Methods that create or change buildings must check them according to the
+// domain rules and return error of ErrValidation kind, which wraps
+// domain.ValidationError, if they are invalid.
+//
+// Errors of the repository must be wrapped, so their kinds, such as
+// ErrNotFound, ErrConflict, ErrValidation or ErrUnavailable, can be checked
+// with errors.Is.
 type BuildingService interface {
+	// Count must count all buildings according to the passed filter parameters,
+	// ignoring pagination, or return an error.
+	Count(ctx context.Context, filters *BuildingFilters) (uint64, error)
+
 	// Create must create a structure within the system or return an error. For
 	// example, insert into the repository.
 	Create(
 		ctx context.Context, building *domain.BuildingInfo) (*domain.Building, error)
 
+	// CreateBatch must create passed buildings within the system and return
+	// result of each of them in the same order or return an error if batch can
+	// not be processed at all. If batch is atomic, either all buildings are
+	// created or none of them. Buildings must be normalized and validated
+	// according to the domain rules by the caller.
+	CreateBatch(
+		ctx context.Context,
+		infos []*domain.BuildingInfo,
+		atomic bool) ([]*BuildingBatchResult, error)
+
+	// Delete must delete a building by its identifier or return an error.
+	Delete(ctx context.Context, id int64) error
+
+	// FindDuplicates must find at most limit pairs of likely duplicate buildings
+	// according to the passed filter parameters and criteria or return an
+	// error. Buildings of a pair must have the same city and handover year.
+	FindDuplicates(
+		ctx context.Context,
+		filters *BuildingFilters,
+		criteria *DuplicateCriteria,
+		limit uint64) ([]*BuildingDuplicate, error)
+
+	// Get must get a building by its identifier or return an error.
+	Get(ctx context.Context, id int64) (*domain.Building, error)
+
 	// GetAll must get all buildings according to the passed filter parameters or
 	// return an error.
 	GetAll(
 		ctx context.Context, filters *BuildingFilters) ([]*domain.Building, error)
 
+	// GetEach must pass each building to the callback according to the passed
+	// filter parameters without getting all of them at once or return an error.
+	// Iteration must stop on the first callback error and return it wrapped.
+	GetEach(
+		ctx context.Context,
+		filters *BuildingFilters,
+		fn func(*domain.Building) error) error
+
+	// GetFacets must count buildings by distinct values of each passed field
+	// according to the passed filter parameters, ignoring the filter on the
+	// field itself, or return an error.
+	GetFacets(
+		ctx context.Context,
+		filters *BuildingFilters,
+		fields []BuildingField) (*BuildingFacets, error)
+
+	// GetStats must get aggregate statistics of the buildings according to the
+	// passed filter parameters or return an error.
+	GetStats(
+		ctx context.Context, filters *BuildingFilters) (*BuildingStats, error)
+
+	// Import must create all buildings of passed source within the system and
+	// return their count or return an error. Either all buildings are created or
+	// none of them.
+	Import(ctx context.Context, source BuildingInfoSource) (uint64, error)
+
+	// ImportFeed must parse Yandex.Realty style feed of passed source and
+	// upsert its complexes by their external identifiers, so reimport does not
+	// duplicate buildings, or return an error.
+	ImportFeed(
+		ctx context.Context,
+		source string,
+		reader io.Reader) (*FeedImportResult, error)
+
 	// Init must initialize service before work.
 	Init(ctx context.Context) error
+
+	// Iterate must open an iterator over buildings according to the passed
+	// filter parameters or return an error. Buildings must be fetched lazily as
+	// the iterator is advanced. Iterator must be closed by the caller.
+	Iterate(
+		ctx context.Context, filters *BuildingFilters) (BuildingIterator, error)
+
+	// Merge must merge a duplicate building into a building with passed
+	// identifier and return the resulting building or return an error. If one
+	// of the buildings does not exist, ErrBuildingNotFound must be returned.
+	Merge(ctx context.Context, id, duplicateId int64) (*domain.Building, error)
+
+	// Patch must change only passed fields of a building with passed identifier
+	// or return an error.
+	Patch(
+		ctx context.Context,
+		id int64,
+		patch *BuildingPatch) (*domain.Building, error)
+
+	// Suggest must get at most limit most frequent distinct values of passed
+	// field that start with passed prefix or return an error.
+	Suggest(
+		ctx context.Context,
+		field BuildingField,
+		prefix string,
+		limit uint64) ([]*BuildingSuggestion, error)
+
+	// Update must replace information of a building with passed identifier or
+	// return an error.
+	Update(
+		ctx context.Context,
+		id int64,
+		info *domain.BuildingInfo) (*domain.Building, error)
+
+	// Upsert must create passed building or update the one with the same
+	// external identifier and return it and whether it is created or return an
+	// error.
+	Upsert(
+		ctx context.Context,
+		info *ExternalBuildingInfo) (*domain.Building, bool, error)
 }
@@ -3,6 +3,8 @@ package logic
 import (
 	"context"
 	"fmt"
+	"io"
+	"slices"
 
 	"github.com/rylenko/leadgen-market-task/internal/domain"
 )
@@ -13,14 +15,131 @@ type BuildingServiceImpl struct {
 	repository BuildingRepository
 }
 
+// Count counts all buildings according to the passed filter parameters,
+// ignoring pagination, or returns an error.
+func (service *BuildingServiceImpl) Count(
+		ctx context.Context, filters *BuildingFilters) (uint64, error) {
+	// Try to count buildings using repository and accepted filter parameters.
+	count, err := service.repository.Count(ctx, filters)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"failed to count buildings with filter params %+v: %w", filters, err)
+	}
+
+	return count, nil
+}
+
 // Create inserts passed building to the database or returns an error.
 func (service *BuildingServiceImpl) Create(
 		ctx context.Context, info *domain.BuildingInfo) (*domain.Building, error) {
+	// Check that building is valid.
+	if err := prepareBuildingInfo(info); err != nil {
+		return nil, err
+	}
+
 	// Try to insert accepted building to the repository.
 	building, err := service.repository.Insert(ctx, info)
 	if err != nil {
 		return nil, fmt.Errorf(
-			"failed to insert building to the repository: %v", err)
+			"failed to insert building to the repository: %w", err)
+	}
+
+	return building, nil
+}
+
+// CreateBatch inserts passed buildings to the repository and returns result of
+// each of them in the same order or returns an error if batch can not be
+// processed at all. Buildings must be already normalized and validated.
+func (service *BuildingServiceImpl) CreateBatch(
+		ctx context.Context,
+		infos []*domain.BuildingInfo,
+		atomic bool) ([]*BuildingBatchResult, error) {
+	// Try to insert buildings to the repository.
+	results, err := service.repository.InsertBatch(ctx, infos, atomic)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to insert batch of %d buildings to the repository: %w",
+			len(infos),
+			err)
+	}
+
+	return results, nil
+}
+
+// Delete deletes a building by its identifier or returns an error.
+func (service *BuildingServiceImpl) Delete(
+		ctx context.Context, id int64) error {
+	// Try to delete a building from the repository.
+	if err := service.repository.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete building %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// FindDuplicates finds at most limit pairs of likely duplicate buildings
+// according to the passed filter parameters and criteria or returns an error.
+//
+// Buildings are iterated in order of city and handover year, so only buildings
+// of a single group are held in memory.
+func (service *BuildingServiceImpl) FindDuplicates(
+		ctx context.Context,
+		filters *BuildingFilters,
+		criteria *DuplicateCriteria,
+		limit uint64) ([]*BuildingDuplicate, error) {
+	// Try to open iterator over buildings ordered by groups. Filters are copied,
+	// so sort keys of the caller are not changed.
+	groupedFilters := *filters
+	groupedFilters.WithSort([]*BuildingSortKey{
+		NewBuildingSortKey(BuildingFieldCity, false),
+		NewBuildingSortKey(BuildingFieldHandoverYear, false),
+		NewBuildingSortKey(BuildingFieldId, false),
+	})
+	iterator, err := service.repository.Iterate(ctx, &groupedFilters)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to iterate buildings with filter params %+v: %w", filters, err)
+	}
+	defer iterator.Close()
+
+	var (
+		duplicates []*BuildingDuplicate
+		group []*domain.Building
+	)
+	for uint64(len(duplicates)) < limit {
+		// Find duplicates of the group when it is complete.
+		next := iterator.Next()
+		var building *domain.Building
+		if next {
+			building = iterator.Building()
+		}
+		if len(group) > 0 && (!next ||
+				building.Info.City != group[0].Info.City ||
+				building.Info.HandoverYear != group[0].Info.HandoverYear) {
+			duplicates = append(duplicates, findDuplicatesInGroup(
+				group, criteria, limit - uint64(len(duplicates)))...)
+			group = group[:0]
+		}
+		if !next {
+			break
+		}
+
+		group = append(group, building)
+	}
+	if err := iterator.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate buildings: %w", err)
+	}
+
+	return duplicates, nil
+}
+
+// Get gets a building by its identifier or returns an error.
+func (service *BuildingServiceImpl) Get(
+		ctx context.Context, id int64) (*domain.Building, error) {
+	// Try to get a building from the repository.
+	building, err := service.repository.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get building %d: %w", id, err)
 	}
 
 	return building, nil
@@ -34,22 +153,260 @@ func (service *BuildingServiceImpl) GetAll(
 	buildings, err := service.repository.GetAll(ctx, filters)
 	if err != nil {
 		return nil, fmt.Errorf(
-			"failed to get all buildings with filter params %+v: %v", filters, err)
+			"failed to get all buildings with filter params %+v: %w", filters, err)
 	}
 
 	return buildings, nil
 }
 
+// GetEach passes each building to the callback according to the passed
+// filter parameters or returns an error. Buildings are got from the repository
+// iterator one by one. Iteration stops on the first callback error.
+func (service *BuildingServiceImpl) GetEach(
+		ctx context.Context,
+		filters *BuildingFilters,
+		fn func(*domain.Building) error) error {
+	// Try to open iterator using repository.
+	iterator, err := service.repository.Iterate(ctx, filters)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to iterate buildings with filter params %+v: %w", filters, err)
+	}
+	defer iterator.Close()
+
+	// Pass buildings to the callback one by one.
+	for iterator.Next() {
+		if err := fn(iterator.Building()); err != nil {
+			return fmt.Errorf("callback failed: %w", err)
+		}
+	}
+	if err := iterator.Err(); err != nil {
+		return fmt.Errorf(
+			"failed to iterate buildings with filter params %+v: %w", filters, err)
+	}
+
+	return nil
+}
+
+// GetFacets counts buildings by distinct values of each passed field according
+// to the passed filter parameters, ignoring the filter on the field itself, or
+// returns an error. If at least one of the fields can not be used as facet,
+// ErrUnfacetableBuildingField is returned.
+func (service *BuildingServiceImpl) GetFacets(
+		ctx context.Context,
+		filters *BuildingFilters,
+		fields []BuildingField) (*BuildingFacets, error) {
+	// Check that all fields can be used as facets.
+	for _, field := range fields {
+		if !slices.Contains(FacetableBuildingFields, field) {
+			return nil, ErrUnfacetableBuildingField
+		}
+	}
+
+	// Try to get facets using repository and accepted filter parameters.
+	facets, err := service.repository.GetFacets(ctx, filters, fields)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get facets %v with filter params %+v: %w",
+			fields,
+			filters,
+			err)
+	}
+
+	return facets, nil
+}
+
+// GetStats gets aggregate statistics of the buildings according to the passed
+// filter parameters or returns an error.
+func (service *BuildingServiceImpl) GetStats(
+		ctx context.Context, filters *BuildingFilters) (*BuildingStats, error) {
+	// Try to get statistics using repository and accepted filter parameters.
+	stats, err := service.repository.GetStats(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get stats with filter params %+v: %w", filters, err)
+	}
+
+	return stats, nil
+}
+
+// Import inserts all buildings of passed source to the repository and returns
+// their count or returns an error. If one of the buildings is invalid, none of
+// them are inserted and error of ErrValidation kind is returned.
+func (service *BuildingServiceImpl) Import(
+		ctx context.Context, source BuildingInfoSource) (uint64, error) {
+	// Try to insert buildings of the source to the repository, stopping on the
+	// first invalid one.
+	validating := &validatingBuildingInfoSource{source: source}
+	count, err := service.repository.InsertStream(ctx, validating)
+	if validating.err != nil {
+		return 0, validating.err
+	} else if err != nil {
+		return 0, fmt.Errorf(
+			"failed to insert stream of buildings to the repository: %w", err)
+	}
+
+	return count, nil
+}
+
+// ImportFeed parses Yandex.Realty style feed of passed source and upserts its
+// complexes by their external identifiers or returns an error.
+// ErrInvalidRealtyFeed is returned if the feed can not be parsed.
+func (service *BuildingServiceImpl) ImportFeed(
+		ctx context.Context,
+		source string,
+		reader io.Reader) (*FeedImportResult, error) {
+	// Try to parse feed.
+	feed, err := ParseRealtyFeed(reader, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse feed of %s: %w", source, err)
+	}
+	result := &FeedImportResult{Skipped: feed.Skipped}
+	if len(feed.Infos) == 0 {
+		return result, nil
+	}
+
+	// Try to upsert complexes of the feed using repository.
+	counts, err := service.repository.UpsertBatch(ctx, feed.Infos)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to upsert %d buildings of %s feed: %w",
+			len(feed.Infos),
+			source,
+			err)
+	}
+	result.BuildingUpsertCounts = *counts
+
+	return result, nil
+}
+
 // Initializes service before work. For example, initializes repository.
 func (service *BuildingServiceImpl) Init(ctx context.Context) error {
 	// Try to initialize service repository.
 	if err := service.repository.Init(ctx); err != nil {
-		return fmt.Errorf("failed to init repository: %v", err)
+		return fmt.Errorf("failed to init repository: %w", err)
 	}
 
 	return nil
 }
 
+// Iterate opens an iterator over buildings according to the passed filter
+// parameters or returns an error. Iterator must be closed by the caller.
+func (service *BuildingServiceImpl) Iterate(
+		ctx context.Context,
+		filters *BuildingFilters) (BuildingIterator, error) {
+	// Try to open iterator using repository.
+	iterator, err := service.repository.Iterate(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to iterate buildings with filter params %+v: %w", filters, err)
+	}
+
+	return iterator, nil
+}
+
+// Merge merges a duplicate building into a building with passed identifier
+// and returns the resulting building or returns an error. ErrSelfMerge is
+// returned if identifiers are equal.
+func (service *BuildingServiceImpl) Merge(
+		ctx context.Context, id, duplicateId int64) (*domain.Building, error) {
+	if id == duplicateId {
+		return nil, ErrSelfMerge
+	}
+
+	// Try to merge buildings using repository.
+	building, err := service.repository.Merge(ctx, id, duplicateId)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to merge building %d into %d: %w", duplicateId, id, err)
+	}
+
+	return building, nil
+}
+
+// Patch changes only passed fields of a building with passed identifier or
+// returns an error.
+func (service *BuildingServiceImpl) Patch(
+		ctx context.Context,
+		id int64,
+		patch *BuildingPatch) (*domain.Building, error) {
+	// Check that changed fields are valid.
+	if err := prepareBuildingPatch(patch); err != nil {
+		return nil, err
+	}
+
+	// Try to patch a building in the repository.
+	building, err := service.repository.Patch(ctx, id, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch building %d: %w", id, err)
+	}
+
+	return building, nil
+}
+
+// Suggest gets at most limit most frequent distinct values of passed field
+// that start with passed prefix or returns an error. If values of the field can
+// not be suggested, ErrUnsuggestableBuildingField is returned.
+func (service *BuildingServiceImpl) Suggest(
+		ctx context.Context,
+		field BuildingField,
+		prefix string,
+		limit uint64) ([]*BuildingSuggestion, error) {
+	// Check that values of the field can be suggested.
+	if !slices.Contains(SuggestableBuildingFields, field) {
+		return nil, ErrUnsuggestableBuildingField
+	}
+
+	// Try to get suggestions from the repository.
+	suggestions, err := service.repository.Suggest(ctx, field, prefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to suggest %s values with prefix %q: %w", field, prefix, err)
+	}
+
+	return suggestions, nil
+}
+
+// Update replaces information of a building with passed identifier or returns
+// an error.
+func (service *BuildingServiceImpl) Update(
+		ctx context.Context,
+		id int64,
+		info *domain.BuildingInfo) (*domain.Building, error) {
+	// Check that building is valid.
+	if err := prepareBuildingInfo(info); err != nil {
+		return nil, err
+	}
+
+	// Try to update a building in the repository.
+	building, err := service.repository.Update(ctx, id, info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update building %d: %w", id, err)
+	}
+
+	return building, nil
+}
+
+// Upsert creates passed building or updates the one with the same external
+// identifier and returns it and whether it is created or returns an error.
+func (service *BuildingServiceImpl) Upsert(
+		ctx context.Context,
+		info *ExternalBuildingInfo) (*domain.Building, bool, error) {
+	// Check that building is valid.
+	if err := prepareBuildingInfo(info.Info); err != nil {
+		return nil, false, err
+	}
+
+	// Try to upsert building using repository.
+	building, created, err := service.repository.Upsert(ctx, info)
+	if err != nil {
+		return nil, false, fmt.Errorf(
+			"failed to upsert building %+v: %w", info.ExternalId, err)
+	}
+
+	return building, created, nil
+}
+
 // NewBuildingServiceImpl creates a new instance of building service
 // implementation using passed repository.
 func NewBuildingServiceImpl(
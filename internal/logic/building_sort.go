@@ -0,0 +1,32 @@
+package logic
+
+// BuildingSortKey describes a field by which buildings are sorted and a
+// direction of the sorting.
+type BuildingSortKey struct {
+	Field BuildingField
+	Descending bool
+}
+
+// NewBuildingSortKey creates a new instance of sort key.
+func NewBuildingSortKey(
+		field BuildingField, descending bool) *BuildingSortKey {
+	return &BuildingSortKey{
+		Field: field,
+		Descending: descending,
+	}
+}
+
+// CompleteBuildingSort returns sort keys that define a total order of the
+// buildings. Identifier is appended as the last key if it is not already
+// used, so buildings with equal values of other keys are still ordered.
+func CompleteBuildingSort(sort []*BuildingSortKey) []*BuildingSortKey {
+	for _, key := range sort {
+		if key.Field == BuildingFieldId {
+			return sort
+		}
+	}
+
+	completed := make([]*BuildingSortKey, len(sort), len(sort) + 1)
+	copy(completed, sort)
+	return append(completed, NewBuildingSortKey(BuildingFieldId, false))
+}
@@ -0,0 +1,18 @@
+package logic
+
+// BuildingStats contains aggregate statistics of the buildings.
+//
+// Floors count statistics are pointers. They are nil if there are no
+// buildings.
+type BuildingStats struct {
+	Count uint64
+
+	// Counts of buildings by distinct values of the fields, ordered by count in
+	// descending order.
+	CityCounts []*ValueCount[string]
+	HandoverYearCounts []*ValueCount[uint64]
+
+	MinFloorsCount *uint64
+	MaxFloorsCount *uint64
+	AvgFloorsCount *float64
+}
@@ -0,0 +1,30 @@
+package logic
+
+// ErrUnsuggestableBuildingField is returned when suggestions are requested for
+// a field which values can not be suggested.
+var ErrUnsuggestableBuildingField = NewError(
+		ErrInvalidArgument,
+		"unsuggestable_building_field",
+		"building field values can not be suggested")
+
+// SuggestableBuildingFields contains building fields which values can be
+// suggested.
+var SuggestableBuildingFields = []BuildingField{
+	BuildingFieldName,
+	BuildingFieldCity,
+}
+
+// BuildingSuggestion is a distinct value of the building field with the count
+// of buildings that have it.
+type BuildingSuggestion struct {
+	Value string
+	Count uint64
+}
+
+// NewBuildingSuggestion creates a new instance of building suggestion.
+func NewBuildingSuggestion(value string, count uint64) *BuildingSuggestion {
+	return &BuildingSuggestion{
+		Value: value,
+		Count: count,
+	}
+}
@@ -0,0 +1,36 @@
+package logic
+
+import "github.com/rylenko/leadgen-market-task/internal/domain"
+
+// ExternalBuildingInfo is information about a building from an external
+// system together with its identifier there.
+type ExternalBuildingInfo struct {
+	ExternalId *domain.ExternalId
+	Info *domain.BuildingInfo
+}
+
+// NewExternalBuildingInfo creates a new instance of external building
+// information.
+func NewExternalBuildingInfo(
+		externalId *domain.ExternalId,
+		info *domain.BuildingInfo) *ExternalBuildingInfo {
+	return &ExternalBuildingInfo{
+		ExternalId: externalId,
+		Info: info,
+	}
+}
+
+// BuildingUpsertCounts is a count of buildings that are inserted and updated by
+// upsert.
+type BuildingUpsertCounts struct {
+	Inserted uint64
+	Updated uint64
+}
+
+// FeedImportResult is a result of importing a feed.
+type FeedImportResult struct {
+	BuildingUpsertCounts
+
+	// Descriptions of feed entries that are skipped.
+	Skipped []string
+}
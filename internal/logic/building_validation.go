@@ -0,0 +1,86 @@
+package logic
+
+import (
+	"strings"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+)
+
+// Normalizes building information and checks that it is valid. Returns error
+// of ErrValidation kind, which wraps domain.ValidationError, if it is not.
+func prepareBuildingInfo(info *domain.BuildingInfo) error {
+	domain.NormalizeBuildingInfo(info)
+	if err := domain.ValidateBuildingInfo(info); err != nil {
+		return wrapValidationError(err)
+	}
+	return nil
+}
+
+// Normalizes fields of the patch and checks that changed ones are valid.
+// Returns error of ErrValidation kind, which wraps domain.ValidationError, if
+// they are not.
+func prepareBuildingPatch(patch *BuildingPatch) error {
+	var errs []*domain.FieldError
+	addErr := func(fieldErr *domain.FieldError) {
+		if fieldErr != nil {
+			errs = append(errs, fieldErr)
+		}
+	}
+
+	if patch.Name != nil {
+		name := strings.TrimSpace(*patch.Name)
+		patch.Name = &name
+		addErr(domain.ValidateBuildingName(name))
+	}
+	if patch.City != nil {
+		city := strings.TrimSpace(*patch.City)
+		patch.City = &city
+		addErr(domain.ValidateBuildingCity(city))
+	}
+	if patch.HandoverYear != nil {
+		addErr(domain.ValidateBuildingHandoverYear(*patch.HandoverYear))
+	}
+	if patch.FloorsCount != nil {
+		addErr(domain.ValidateBuildingFloorsCount(*patch.FloorsCount))
+	}
+
+	if len(errs) > 0 {
+		return wrapValidationError(domain.NewValidationError(errs...))
+	}
+	return nil
+}
+
+// Wraps passed domain.ValidationError into the error of ErrValidation kind.
+func wrapValidationError(err error) error {
+	return WrapError(ErrValidation, "validation_failed", "invalid building", err)
+}
+
+// Source of building information which stops with an error on the first
+// invalid building.
+type validatingBuildingInfoSource struct {
+	source BuildingInfoSource
+	err error
+}
+
+func (source *validatingBuildingInfoSource) Next() bool {
+	if source.err != nil || !source.source.Next() {
+		return false
+	}
+
+	if err := prepareBuildingInfo(source.source.Info()); err != nil {
+		source.err = err
+		return false
+	}
+	return true
+}
+
+func (source *validatingBuildingInfoSource) Info() *domain.BuildingInfo {
+	return source.source.Info()
+}
+
+func (source *validatingBuildingInfoSource) Err() error {
+	if source.err != nil {
+		return source.err
+	}
+	return source.source.Err()
+}
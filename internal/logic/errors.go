@@ -0,0 +1,72 @@
+package logic
+
+import "errors"
+
+// Kinds of the errors. Every Error has one of them, so callers are able to
+// react to the whole class of failures via errors.Is without knowing concrete
+// errors.
+var (
+	// ErrInvalidArgument is a kind of errors caused by malformed arguments, such
+	// as unknown fields or broken cursors.
+	ErrInvalidArgument = errors.New("invalid argument")
+
+	// ErrNotFound is a kind of errors caused by absence of a requested entity.
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is a kind of errors caused by conflict with the current state
+	// of an entity, such as unique violation.
+	ErrConflict = errors.New("conflict")
+
+	// ErrValidation is a kind of errors caused by values which does not satisfy
+	// the rules of the domain or the storage.
+	ErrValidation = errors.New("validation failed")
+
+	// ErrUnavailable is a kind of errors caused by temporary unavailability of
+	// the storage. Operation may be retried later.
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// Error of the logic with kind and machine-readable code.
+type Error struct {
+	// One of the error kinds above.
+	Kind error
+	// Machine-readable code of the error, for example "building_not_found".
+	Code string
+	// Human-readable message of the error.
+	Message string
+	// Optional cause of the error.
+	Err error
+}
+
+// Creates a new error of passed kind with passed code and message.
+func NewError(kind error, code string, message string) *Error {
+	return &Error{
+		Kind: kind,
+		Code: code,
+		Message: message,
+	}
+}
+
+// Creates a new error of passed kind with passed code and message, which is
+// caused by passed error.
+func WrapError(kind error, code string, message string, err error) *Error {
+	e := NewError(kind, code, message)
+	e.Err = err
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Reports whether target is the kind of the error.
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
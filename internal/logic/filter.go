@@ -0,0 +1,101 @@
+package logic
+
+// ErrUnknownFilterOperator is returned when passed name does not match any
+// filter operator.
+var ErrUnknownFilterOperator = NewError(
+		ErrInvalidArgument, "unknown_filter_operator", "unknown filter operator")
+
+// FilterOperator is a name of comparison used by filter.
+type FilterOperator string
+
+const (
+	FilterOperatorEq FilterOperator = "eq"
+	FilterOperatorNe FilterOperator = "ne"
+	FilterOperatorGt FilterOperator = "gt"
+	FilterOperatorGte FilterOperator = "gte"
+	FilterOperatorLt FilterOperator = "lt"
+	FilterOperatorLte FilterOperator = "lte"
+	FilterOperatorIn FilterOperator = "in"
+	FilterOperatorNotIn FilterOperator = "nin"
+)
+
+// FilterOperators contains all known filter operators.
+var FilterOperators = []FilterOperator{
+	FilterOperatorEq,
+	FilterOperatorNe,
+	FilterOperatorGt,
+	FilterOperatorGte,
+	FilterOperatorLt,
+	FilterOperatorLte,
+	FilterOperatorIn,
+	FilterOperatorNotIn,
+}
+
+// ParseFilterOperator parses filter operator from its name or returns
+// ErrUnknownFilterOperator.
+func ParseFilterOperator(name string) (FilterOperator, error) {
+	for _, operator := range FilterOperators {
+		if string(operator) == name {
+			return operator, nil
+		}
+	}
+	return "", ErrUnknownFilterOperator
+}
+
+// FilterValue is a type of values that can be filtered.
+type FilterValue interface {
+	~string | ~uint64
+}
+
+// Filter contains conditions on a single field. A value matches the filter if
+// it meets all set conditions.
+//
+// Condition values are pointers and slices. If one of them is nil, then the
+// condition is not set. Empty In slice matches nothing and empty NotIn slice
+// matches everything.
+type Filter[T FilterValue] struct {
+	Eq *T
+	Ne *T
+	Gt *T
+	Gte *T
+	Lt *T
+	Lte *T
+	In []T
+	NotIn []T
+}
+
+// NewEqFilter creates a new filter that matches only passed value.
+func NewEqFilter[T FilterValue](value T) *Filter[T] {
+	return &Filter[T]{Eq: &value}
+}
+
+// Set sets condition with passed operator. Single value operators use only the
+// first of passed values.
+func (filter *Filter[T]) Set(operator FilterOperator, values []T) {
+	// Set operators if values are passed.
+	if len(values) > 0 {
+		value := values[0]
+		switch operator {
+		case FilterOperatorEq:
+			filter.Eq = &value
+		case FilterOperatorNe:
+			filter.Ne = &value
+		case FilterOperatorGt:
+			filter.Gt = &value
+		case FilterOperatorGte:
+			filter.Gte = &value
+		case FilterOperatorLt:
+			filter.Lt = &value
+		case FilterOperatorLte:
+			filter.Lte = &value
+		}
+	}
+
+	// Set multiple values operators.
+	switch operator {
+	case FilterOperatorIn:
+		filter.In = append([]T{}, values...)
+	case FilterOperatorNotIn:
+		filter.NotIn = append([]T{}, values...)
+	}
+}
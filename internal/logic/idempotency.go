@@ -0,0 +1,44 @@
+package logic
+
+var (
+	// ErrIdempotencyKeyInProgress is returned if a request with the same
+	// idempotency key is still processed.
+	ErrIdempotencyKeyInProgress = NewError(
+			ErrConflict,
+			"idempotency_key_in_progress",
+			"request with the same idempotency key is in progress")
+
+	// ErrIdempotencyKeyReused is returned if idempotency key is already used by
+	// a different request.
+	ErrIdempotencyKeyReused = NewError(
+			ErrInvalidArgument,
+			"idempotency_key_reused",
+			"idempotency key is already used by a different request")
+)
+
+// IdempotentResponse is a stored response of a request with idempotency key,
+// which is returned again for retries of the request.
+type IdempotentResponse struct {
+	Status int
+	ContentType string
+	Body []byte
+}
+
+// NewIdempotentResponse creates a new instance of idempotent response.
+func NewIdempotentResponse(
+		status int, contentType string, body []byte) *IdempotentResponse {
+	return &IdempotentResponse{
+		Status: status,
+		ContentType: contentType,
+		Body: body,
+	}
+}
+
+// IdempotencyRecord is a stored state of the idempotency key.
+type IdempotencyRecord struct {
+	// Hash of the request which used the key first.
+	RequestHash string
+
+	// Response of the request, which is nil while the request is in progress.
+	Response *IdempotentResponse
+}
@@ -0,0 +1,33 @@
+package logic
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotencyRepository is an interface that describes the required
+// capabilities of the idempotency key repository.
+type IdempotencyRepository interface {
+	// Complete must store response of the request with passed key or return an
+	// error.
+	Complete(
+		ctx context.Context, key string, response *IdempotentResponse) error
+
+	// Init must initialize repository before queries.
+	Init(ctx context.Context) error
+
+	// Release must remove reservation of passed key, so the request can be
+	// retried, or return an error.
+	Release(ctx context.Context, key string) error
+
+	// Reserve must reserve passed key for the request with passed hash and
+	// return nil or return the existing record of the key or an error. Keys
+	// created before expiredBefore and reservations without responses created
+	// before abandonedBefore must be considered absent.
+	Reserve(
+		ctx context.Context,
+		key string,
+		requestHash string,
+		expiredBefore time.Time,
+		abandonedBefore time.Time) (*IdempotencyRecord, error)
+}
@@ -0,0 +1,29 @@
+package logic
+
+import "context"
+
+// IdempotencyService is an interface that describes the required capabilities
+// of the idempotency key service.
+type IdempotencyService interface {
+	// Begin must reserve passed key for the request with passed hash and return
+	// nil or return the stored response of the request or an error. If the
+	// request is still processed, ErrIdempotencyKeyInProgress must be returned.
+	// If the key is used by a request with a different hash,
+	// ErrIdempotencyKeyReused must be returned.
+	Begin(
+		ctx context.Context,
+		key string,
+		requestHash string) (*IdempotentResponse, error)
+
+	// Complete must store response of the request with passed key or return an
+	// error.
+	Complete(
+		ctx context.Context, key string, response *IdempotentResponse) error
+
+	// Init must initialize service before work.
+	Init(ctx context.Context) error
+
+	// Release must remove reservation of passed key, so the request can be
+	// retried, or return an error.
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,91 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// Duration during which stored responses are returned for retries.
+	idempotencyKeyTTL = 24 * time.Hour
+
+	// Duration after which reservation without response is considered
+	// abandoned, for example, because of a crash during the request.
+	idempotencyLockTimeout = time.Minute
+)
+
+// IdempotencyService implementation that interacts with the repository to
+// store responses of the requests.
+type IdempotencyServiceImpl struct {
+	repository IdempotencyRepository
+}
+
+// Begin reserves passed key for the request with passed hash and returns nil
+// or returns the stored response of the request or an error.
+func (service *IdempotencyServiceImpl) Begin(
+		ctx context.Context,
+		key string,
+		requestHash string) (*IdempotentResponse, error) {
+	// Try to reserve the key using repository.
+	now := time.Now()
+	record, err := service.repository.Reserve(
+		ctx,
+		key,
+		requestHash,
+		now.Add(-idempotencyKeyTTL),
+		now.Add(-idempotencyLockTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	// Check the existing record of the key.
+	switch {
+	case record == nil:
+		return nil, nil
+	case record.RequestHash != requestHash:
+		return nil, ErrIdempotencyKeyReused
+	case record.Response == nil:
+		return nil, ErrIdempotencyKeyInProgress
+	default:
+		return record.Response, nil
+	}
+}
+
+// Complete stores response of the request with passed key or returns an
+// error.
+func (service *IdempotencyServiceImpl) Complete(
+		ctx context.Context, key string, response *IdempotentResponse) error {
+	if err := service.repository.Complete(ctx, key, response); err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Initializes service before work. For example, initializes repository.
+func (service *IdempotencyServiceImpl) Init(ctx context.Context) error {
+	// Try to initialize service repository.
+	if err := service.repository.Init(ctx); err != nil {
+		return fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	return nil
+}
+
+// Release removes reservation of passed key or returns an error.
+func (service *IdempotencyServiceImpl) Release(
+		ctx context.Context, key string) error {
+	if err := service.repository.Release(ctx, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// NewIdempotencyServiceImpl creates a new instance of idempotency service
+// implementation using passed repository.
+func NewIdempotencyServiceImpl(
+		repository IdempotencyRepository) *IdempotencyServiceImpl {
+	return &IdempotencyServiceImpl{
+		repository: repository,
+	}
+}
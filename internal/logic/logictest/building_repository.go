@@ -0,0 +1,540 @@
+// Package logictest implements tests of the logic interfaces contracts, which
+// are run against each of their implementations.
+package logictest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Buildings which are inserted before filter and sort tests. Names and cities
+// start with different letters, so they are ordered the same way by any
+// collation.
+var fixtureInfos = []*domain.BuildingInfo{
+	domain.NewBuildingInfo("Aurora", "Moscow", 2020, 10),
+	domain.NewBuildingInfo("Birch Grove", "Kazan", 2021, 5),
+	domain.NewBuildingInfo("Cedar Court", "Moscow", 2019, 25),
+	domain.NewBuildingInfo("Dune Tower", "Sochi", 2021, 0),
+	domain.NewBuildingInfo("Elm House", "Kazan", 2020, 10),
+}
+
+// TestBuildingRepository tests that buildings repository created by passed
+// function meets the contract of logic.BuildingRepository. Function is called
+// for each test and must return an initialized repository without buildings.
+// Tests are run sequentially, so the same storage may be cleared and reused.
+func TestBuildingRepository(
+		t *testing.T,
+		newRepository func(t *testing.T) logic.BuildingRepository) {
+	tests := []struct {
+		name string
+		test func(*testing.T, logic.BuildingRepository)
+	}{
+		{"InsertGet", testInsertGet},
+		{"GetNotFound", testGetNotFound},
+		{"InsertNumericRange", testInsertNumericRange},
+		{"InsertBatch", testInsertBatch},
+		{"InsertStream", testInsertStream},
+		{"Update", testUpdate},
+		{"Patch", testPatch},
+		{"Delete", testDelete},
+		{"Upsert", testUpsert},
+		{"UpsertBatch", testUpsertBatch},
+		{"Merge", testMerge},
+		{"Filters", testFilters},
+		{"Sort", testSort},
+		{"OffsetPagination", testOffsetPagination},
+		{"CursorPagination", testCursorPagination},
+		{"SearchCursorPagination", testSearchCursorPagination},
+		{"Iterate", testIterate},
+		{"Suggest", testSuggest},
+		{"GetFacets", testGetFacets},
+		{"GetStats", testGetStats},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.test(t, newRepository(t))
+		})
+	}
+}
+
+// Tests that inserted buildings are got back unchanged, including edge values
+// of the fields.
+func testInsertGet(t *testing.T, repository logic.BuildingRepository) {
+	ctx := context.Background()
+	infos := []*domain.BuildingInfo{
+		domain.NewBuildingInfo("Aurora", "Moscow", 2020, 10),
+		domain.NewBuildingInfo("Ёлки-Палки", "Санкт-Петербург", 1800, 1),
+		domain.NewBuildingInfo("Zero", "Floors", 0, 0),
+		domain.NewBuildingInfo("Max", "Values", math.MaxInt32, math.MaxInt32),
+	}
+
+	ids := make(map[int64]bool)
+	for _, info := range infos {
+		// Insert building and check that it is returned unchanged.
+		inserted, err := repository.Insert(ctx, copyInfo(info))
+		if err != nil {
+			t.Fatalf("failed to insert %+v: %v", info, err)
+		}
+		if !equalInfos(inserted.Info, info) {
+			t.Errorf("inserted %+v, want %+v", inserted.Info, info)
+		}
+		if ids[inserted.Id] {
+			t.Errorf("identifier %d is assigned twice", inserted.Id)
+		}
+		ids[inserted.Id] = true
+
+		// Get building and check that it is stored unchanged.
+		got, err := repository.Get(ctx, inserted.Id)
+		if err != nil {
+			t.Fatalf("failed to get building %d: %v", inserted.Id, err)
+		}
+		if got.Id != inserted.Id || !equalInfos(got.Info, info) {
+			t.Errorf(
+				"got %d %+v, want %d %+v", got.Id, got.Info, inserted.Id, info)
+		}
+		if got.ExternalId != nil {
+			t.Errorf("got external id %+v, want nil", got.ExternalId)
+		}
+	}
+
+	assertCount(t, repository, &logic.BuildingFilters{}, uint64(len(infos)))
+}
+
+// Tests that getting missing building returns logic.ErrBuildingNotFound.
+func testGetNotFound(t *testing.T, repository logic.BuildingRepository) {
+	building := insertBuilding(t, repository, fixtureInfos[0])
+
+	_, err := repository.Get(context.Background(), building.Id + 1000)
+	if !errors.Is(err, logic.ErrBuildingNotFound) {
+		t.Errorf("got error %v, want %v", err, logic.ErrBuildingNotFound)
+	}
+}
+
+// Tests that buildings which numeric values do not fit the storage are
+// rejected with error of logic.ErrValidation kind and are not inserted.
+func testInsertNumericRange(t *testing.T, repository logic.BuildingRepository) {
+	infos := []*domain.BuildingInfo{
+		domain.NewBuildingInfo("Year", "Big", math.MaxInt32 + 1, 1),
+		domain.NewBuildingInfo("Floors", "Big", 2020, math.MaxInt32 + 1),
+		domain.NewBuildingInfo("Year", "Max", math.MaxUint64, 1),
+		domain.NewBuildingInfo("Floors", "Max", 2020, math.MaxUint64),
+	}
+
+	for _, info := range infos {
+		_, err := repository.Insert(context.Background(), copyInfo(info))
+		if !errors.Is(err, logic.ErrValidation) {
+			t.Errorf(
+				"inserted %+v with error %v, want %v",
+				info,
+				err,
+				logic.ErrValidation)
+		}
+	}
+
+	assertCount(t, repository, &logic.BuildingFilters{}, 0)
+}
+
+// Tests that atomic batch is inserted completely or aborted and that rejected
+// building of non-atomic batch does not prevent insertion of others.
+func testInsertBatch(t *testing.T, repository logic.BuildingRepository) {
+	ctx := context.Background()
+	infos := []*domain.BuildingInfo{
+		fixtureInfos[0],
+		domain.NewBuildingInfo("Floors", "Max", 2020, math.MaxUint64),
+		fixtureInfos[1],
+	}
+
+	// Atomic batch with rejected building must be aborted.
+	results, err := repository.InsertBatch(ctx, infos, true)
+	if err != nil {
+		t.Fatalf("failed to insert atomic batch: %v", err)
+	}
+	if len(results) != len(infos) {
+		t.Fatalf("got %d results, want %d", len(results), len(infos))
+	}
+	if !errors.Is(results[1].Err, logic.ErrValidation) {
+		t.Errorf("got error %v, want %v", results[1].Err, logic.ErrValidation)
+	}
+	for _, i := range []int{0, 2} {
+		if !errors.Is(results[i].Err, logic.ErrBuildingBatchAborted) {
+			t.Errorf(
+				"got error %v of building %d, want %v",
+				results[i].Err,
+				i,
+				logic.ErrBuildingBatchAborted)
+		}
+	}
+	assertCount(t, repository, &logic.BuildingFilters{}, 0)
+
+	// Non-atomic batch must insert all buildings except rejected one.
+	results, err = repository.InsertBatch(ctx, infos, false)
+	if err != nil {
+		t.Fatalf("failed to insert non-atomic batch: %v", err)
+	}
+	if len(results) != len(infos) {
+		t.Fatalf("got %d results, want %d", len(results), len(infos))
+	}
+	if !errors.Is(results[1].Err, logic.ErrValidation) {
+		t.Errorf("got error %v, want %v", results[1].Err, logic.ErrValidation)
+	}
+	for _, i := range []int{0, 2} {
+		if results[i].Err != nil {
+			t.Fatalf("failed to insert building %d: %v", i, results[i].Err)
+		}
+		if !equalInfos(results[i].Building.Info, infos[i]) {
+			t.Errorf(
+				"inserted %+v, want %+v", results[i].Building.Info, infos[i])
+		}
+	}
+	assertCount(t, repository, &logic.BuildingFilters{}, 2)
+}
+
+// Tests that stream is inserted completely and that failed stream inserts
+// nothing.
+func testInsertStream(t *testing.T, repository logic.BuildingRepository) {
+	ctx := context.Background()
+
+	// Stream which fails must insert nothing and return error of the source.
+	errSource := errors.New("source failed")
+	source := newBuildingInfoSliceSource(fixtureInfos[:2], errSource)
+	_, err := repository.InsertStream(ctx, source)
+	if !errors.Is(err, errSource) {
+		t.Errorf(
+			"inserted failing stream with error %v, want %v", err, errSource)
+	}
+	assertCount(t, repository, &logic.BuildingFilters{}, 0)
+
+	// Stream with rejected building must insert nothing.
+	infos := []*domain.BuildingInfo{
+		fixtureInfos[0],
+		domain.NewBuildingInfo("Floors", "Max", 2020, math.MaxUint64),
+	}
+	source = newBuildingInfoSliceSource(infos, nil)
+	_, err = repository.InsertStream(ctx, source)
+	if !errors.Is(err, logic.ErrValidation) {
+		t.Errorf(
+			"inserted invalid stream with error %v, want %v",
+			err,
+			logic.ErrValidation)
+	}
+	assertCount(t, repository, &logic.BuildingFilters{}, 0)
+
+	// Valid stream must be inserted completely.
+	source = newBuildingInfoSliceSource(fixtureInfos, nil)
+	count, err := repository.InsertStream(ctx, source)
+	if err != nil {
+		t.Fatalf("failed to insert stream: %v", err)
+	}
+	if count != uint64(len(fixtureInfos)) {
+		t.Errorf("inserted %d buildings, want %d", count, len(fixtureInfos))
+	}
+	buildings := getAll(t, repository, sortById(&logic.BuildingFilters{}))
+	assertNames(t, buildings, getInfoNames(fixtureInfos)...)
+}
+
+// Tests that update replaces building information and rejects values that do
+// not fit the storage.
+func testUpdate(t *testing.T, repository logic.BuildingRepository) {
+	ctx := context.Background()
+	building := insertBuilding(t, repository, fixtureInfos[0])
+
+	// Update building and check that it is changed.
+	info := domain.NewBuildingInfo("Updated", "City", 2000, 0)
+	updated, err := repository.Update(ctx, building.Id, copyInfo(info))
+	if err != nil {
+		t.Fatalf("failed to update building %d: %v", building.Id, err)
+	}
+	if updated.Id != building.Id || !equalInfos(updated.Info, info) {
+		t.Errorf(
+			"updated %d %+v, want %d %+v",
+			updated.Id,
+			updated.Info,
+			building.Id,
+			info)
+	}
+	assertStored(t, repository, building.Id, info)
+
+	// Update with value out of range must change nothing.
+	invalid := domain.NewBuildingInfo("Invalid", "City", 2000, math.MaxUint64)
+	_, err = repository.Update(ctx, building.Id, invalid)
+	if !errors.Is(err, logic.ErrValidation) {
+		t.Errorf("updated with error %v, want %v", err, logic.ErrValidation)
+	}
+	assertStored(t, repository, building.Id, info)
+
+	// Update of missing building must return logic.ErrBuildingNotFound.
+	_, err = repository.Update(ctx, building.Id + 1000, copyInfo(info))
+	if !errors.Is(err, logic.ErrBuildingNotFound) {
+		t.Errorf("got error %v, want %v", err, logic.ErrBuildingNotFound)
+	}
+}
+
+// Tests that patch changes only passed fields and rejects values that do not
+// fit the storage.
+func testPatch(t *testing.T, repository logic.BuildingRepository) {
+	ctx := context.Background()
+	building := insertBuilding(t, repository, fixtureInfos[0])
+
+	// Patch floors count and check that other fields are not changed.
+	patch := logic.NewBuildingPatch(nil, nil, nil, ptr[uint64](0))
+	patched, err := repository.Patch(ctx, building.Id, patch)
+	if err != nil {
+		t.Fatalf("failed to patch building %d: %v", building.Id, err)
+	}
+	info := domain.NewBuildingInfo(
+		building.Info.Name, building.Info.City, building.Info.HandoverYear, 0)
+	if !equalInfos(patched.Info, info) {
+		t.Errorf("patched %+v, want %+v", patched.Info, info)
+	}
+	assertStored(t, repository, building.Id, info)
+
+	// Patch with value out of range must change nothing.
+	patch = logic.NewBuildingPatch(
+		ptr("Invalid"), nil, ptr[uint64](math.MaxUint64), nil)
+	_, err = repository.Patch(ctx, building.Id, patch)
+	if !errors.Is(err, logic.ErrValidation) {
+		t.Errorf("patched with error %v, want %v", err, logic.ErrValidation)
+	}
+	assertStored(t, repository, building.Id, info)
+
+	// Patch of missing building must return logic.ErrBuildingNotFound.
+	patch = logic.NewBuildingPatch(ptr("Missing"), nil, nil, nil)
+	_, err = repository.Patch(ctx, building.Id + 1000, patch)
+	if !errors.Is(err, logic.ErrBuildingNotFound) {
+		t.Errorf("got error %v, want %v", err, logic.ErrBuildingNotFound)
+	}
+}
+
+// Tests that deleted building can not be got and deleted again.
+func testDelete(t *testing.T, repository logic.BuildingRepository) {
+	ctx := context.Background()
+	building := insertBuilding(t, repository, fixtureInfos[0])
+	other := insertBuilding(t, repository, fixtureInfos[1])
+
+	if err := repository.Delete(ctx, building.Id); err != nil {
+		t.Fatalf("failed to delete building %d: %v", building.Id, err)
+	}
+	_, err := repository.Get(ctx, building.Id)
+	if !errors.Is(err, logic.ErrBuildingNotFound) {
+		t.Errorf(
+			"got deleted with error %v, want %v",
+			err,
+			logic.ErrBuildingNotFound)
+	}
+	err = repository.Delete(ctx, building.Id)
+	if !errors.Is(err, logic.ErrBuildingNotFound) {
+		t.Errorf(
+			"deleted twice with error %v, want %v",
+			err,
+			logic.ErrBuildingNotFound)
+	}
+
+	// Other buildings must stay.
+	assertStored(t, repository, other.Id, fixtureInfos[1])
+	assertCount(t, repository, &logic.BuildingFilters{}, 1)
+}
+
+// Tests that iterator passes the same buildings as GetAll and can be closed
+// before it is exhausted.
+func testIterate(t *testing.T, repository logic.BuildingRepository) {
+	ctx := context.Background()
+	insertFixtures(t, repository)
+	filters := sortById(&logic.BuildingFilters{
+		City: &logic.Filter[string]{Ne: ptr("Sochi")},
+	})
+	want := getNames(getAll(t, repository, filters))
+
+	// Collect buildings of the iterator.
+	iterator, err := repository.Iterate(ctx, filters)
+	if err != nil {
+		t.Fatalf("failed to open iterator: %v", err)
+	}
+	var iterated []*domain.Building
+	for iterator.Next() {
+		iterated = append(iterated, iterator.Building())
+	}
+	if err := iterator.Err(); err != nil {
+		t.Fatalf("failed to iterate: %v", err)
+	}
+	iterator.Close()
+	iterator.Close()
+	assertNames(t, iterated, want...)
+
+	// Iterator closed after the first building must release the storage, so
+	// the next queries do not wait for it.
+	iterator, err = repository.Iterate(ctx, filters)
+	if err != nil {
+		t.Fatalf("failed to open iterator: %v", err)
+	}
+	if !iterator.Next() {
+		t.Fatalf("iterator is empty: %v", iterator.Err())
+	}
+	iterator.Close()
+	assertNames(t, getAll(t, repository, filters), want...)
+}
+
+// Source of building information over a slice, which fails with passed error
+// after the last building if the error is not nil.
+type buildingInfoSliceSource struct {
+	infos []*domain.BuildingInfo
+	index int
+	err error
+	failed bool
+}
+
+func (source *buildingInfoSliceSource) Next() bool {
+	if source.index + 1 >= len(source.infos) {
+		source.failed = source.err != nil
+		return false
+	}
+	source.index++
+	return true
+}
+
+func (source *buildingInfoSliceSource) Info() *domain.BuildingInfo {
+	return copyInfo(source.infos[source.index])
+}
+
+func (source *buildingInfoSliceSource) Err() error {
+	if source.failed {
+		return source.err
+	}
+	return nil
+}
+
+// Creates a new source over passed building information.
+func newBuildingInfoSliceSource(
+		infos []*domain.BuildingInfo, err error) *buildingInfoSliceSource {
+	return &buildingInfoSliceSource{infos: infos, index: -1, err: err}
+}
+
+// Checks that count of buildings selected by passed filters is equal to the
+// wanted one.
+func assertCount(
+		t *testing.T,
+		repository logic.BuildingRepository,
+		filters *logic.BuildingFilters,
+		want uint64) {
+	t.Helper()
+
+	count, err := repository.Count(context.Background(), filters)
+	if err != nil {
+		t.Fatalf("failed to count buildings: %v", err)
+	}
+	if count != want {
+		t.Errorf("counted %d buildings, want %d", count, want)
+	}
+}
+
+// Checks that buildings have wanted names in the same order.
+func assertNames(t *testing.T, buildings []*domain.Building, want ...string) {
+	t.Helper()
+
+	if got := getNames(buildings); !slices.Equal(got, want) {
+		t.Errorf("got buildings %q, want %q", got, want)
+	}
+}
+
+// Checks that stored building with passed identifier has wanted information.
+func assertStored(
+		t *testing.T,
+		repository logic.BuildingRepository,
+		id int64,
+		want *domain.BuildingInfo) {
+	t.Helper()
+
+	building, err := repository.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to get building %d: %v", id, err)
+	}
+	if !equalInfos(building.Info, want) {
+		t.Errorf("stored %+v, want %+v", building.Info, want)
+	}
+}
+
+// Copies passed building information, so repository can not change the
+// original one.
+func copyInfo(info *domain.BuildingInfo) *domain.BuildingInfo {
+	infoCopy := *info
+	return &infoCopy
+}
+
+// Checks that building information is equal.
+func equalInfos(a, b *domain.BuildingInfo) bool {
+	return *a == *b
+}
+
+// Gets all buildings selected by passed filters or fails the test.
+func getAll(
+		t *testing.T,
+		repository logic.BuildingRepository,
+		filters *logic.BuildingFilters) []*domain.Building {
+	t.Helper()
+
+	buildings, err := repository.GetAll(context.Background(), filters)
+	if err != nil {
+		t.Fatalf("failed to get buildings with %+v: %v", filters, err)
+	}
+	return buildings
+}
+
+// Gets names of the building information.
+func getInfoNames(infos []*domain.BuildingInfo) []string {
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name)
+	}
+	return names
+}
+
+// Gets names of the buildings.
+func getNames(buildings []*domain.Building) []string {
+	names := make([]string, 0, len(buildings))
+	for _, building := range buildings {
+		names = append(names, building.Info.Name)
+	}
+	return names
+}
+
+// Inserts building with passed information or fails the test.
+func insertBuilding(
+		t *testing.T,
+		repository logic.BuildingRepository,
+		info *domain.BuildingInfo) *domain.Building {
+	t.Helper()
+
+	building, err := repository.Insert(context.Background(), copyInfo(info))
+	if err != nil {
+		t.Fatalf("failed to insert %+v: %v", info, err)
+	}
+	return building
+}
+
+// Inserts fixture buildings in their order, so their identifiers increase.
+func insertFixtures(t *testing.T, repository logic.BuildingRepository) {
+	t.Helper()
+
+	for _, info := range fixtureInfos {
+		insertBuilding(t, repository, info)
+	}
+}
+
+// Returns pointer to passed value.
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// Sorts buildings selected by passed filters by identifier, so their order
+// does not depend on relevance, and returns the filters.
+func sortById(filters *logic.BuildingFilters) *logic.BuildingFilters {
+	return filters.WithSort(
+		[]*logic.BuildingSortKey{
+			logic.NewBuildingSortKey(logic.BuildingFieldId, false),
+		})
+}
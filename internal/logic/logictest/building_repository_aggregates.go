@@ -0,0 +1,207 @@
+package logictest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Tests that suggestions are distinct values which start with the prefix in
+// any case, ordered by count and limited, and that prefix is matched
+// literally.
+func testSuggest(t *testing.T, repository logic.BuildingRepository) {
+	insertFixtures(t, repository)
+
+	cases := []struct {
+		field logic.BuildingField
+		prefix string
+		limit uint64
+		want []string
+	}{
+		{
+			logic.BuildingFieldCity,
+			"",
+			10,
+			[]string{"Kazan:2", "Moscow:2", "Sochi:1"},
+		},
+		{logic.BuildingFieldCity, "", 2, []string{"Kazan:2", "Moscow:2"}},
+		{logic.BuildingFieldCity, "mOS", 10, []string{"Moscow:2"}},
+		{logic.BuildingFieldName, "d", 10, []string{"Dune Tower:1"}},
+		{logic.BuildingFieldName, "Birch G", 10, []string{"Birch Grove:1"}},
+		{logic.BuildingFieldName, "%", 10, []string{}},
+		{logic.BuildingFieldName, "_urora", 10, []string{}},
+		{logic.BuildingFieldCity, "Moscow City", 10, []string{}},
+	}
+	for _, c := range cases {
+		suggestions, err := repository.Suggest(
+			context.Background(), c.field, c.prefix, c.limit)
+		if err != nil {
+			t.Fatalf("failed to suggest %s by %q: %v", c.field, c.prefix, err)
+		}
+
+		got := make([]string, 0, len(suggestions))
+		for _, suggestion := range suggestions {
+			got = append(
+				got, fmt.Sprintf("%s:%d", suggestion.Value, suggestion.Count))
+		}
+		if !slices.Equal(got, c.want) {
+			t.Errorf(
+				"got suggestions %q of %s by %q with limit %d, want %q",
+				got,
+				c.field,
+				c.prefix,
+				c.limit,
+				c.want)
+		}
+	}
+}
+
+// Tests that each facet is counted under all filters except the one on its
+// own field and that facets which are not requested are nil.
+func testGetFacets(t *testing.T, repository logic.BuildingRepository) {
+	ctx := context.Background()
+	insertFixtures(t, repository)
+	filters := &logic.BuildingFilters{
+		City: &logic.Filter[string]{Eq: ptr("Moscow")},
+		HandoverYear: &logic.Filter[uint64]{Gte: ptr[uint64](2020)},
+	}
+
+	// All facets must be counted.
+	facets, err := repository.GetFacets(
+		ctx, filters, logic.FacetableBuildingFields)
+	if err != nil {
+		t.Fatalf("failed to get facets with %+v: %v", filters, err)
+	}
+	assertValueCounts(t, "city", facets.City, "Kazan:2", "Moscow:1", "Sochi:1")
+	assertValueCounts(
+		t, "handover year", facets.HandoverYear, "2019:1", "2020:1")
+	assertValueCounts(t, "floors count", facets.FloorsCount, "10:1")
+
+	// Only requested facets must be counted and they are not nil even if no
+	// buildings are selected.
+	filters = &logic.BuildingFilters{
+		City: &logic.Filter[string]{Eq: ptr("Moscow")},
+		FloorsCount: &logic.Filter[uint64]{Gt: ptr[uint64](100)},
+	}
+	fields := []logic.BuildingField{logic.BuildingFieldHandoverYear}
+	facets, err = repository.GetFacets(ctx, filters, fields)
+	if err != nil {
+		t.Fatalf("failed to get facets with %+v: %v", filters, err)
+	}
+	if facets.City != nil || facets.FloorsCount != nil {
+		t.Errorf("got facets %+v, want only handover year", facets)
+	}
+	if facets.HandoverYear == nil {
+		t.Errorf("got nil handover year facet, want empty")
+	}
+	assertValueCounts(t, "handover year", facets.HandoverYear)
+}
+
+// Tests statistics of all buildings, of filtered ones and of no buildings.
+func testGetStats(t *testing.T, repository logic.BuildingRepository) {
+	ctx := context.Background()
+	insertFixtures(t, repository)
+
+	// Statistics of all buildings.
+	stats, err := repository.GetStats(ctx, &logic.BuildingFilters{})
+	if err != nil {
+		t.Fatalf("failed to get stats: %v", err)
+	}
+	assertStats(t, stats, 5, 0, 25, 10)
+	assertValueCounts(
+		t, "city", stats.CityCounts, "Kazan:2", "Moscow:2", "Sochi:1")
+	assertValueCounts(
+		t,
+		"handover year",
+		stats.HandoverYearCounts,
+		"2020:2",
+		"2021:2",
+		"2019:1")
+
+	// Statistics of the filtered buildings.
+	filters := &logic.BuildingFilters{
+		City: &logic.Filter[string]{Eq: ptr("Moscow")},
+	}
+	stats, err = repository.GetStats(ctx, filters)
+	if err != nil {
+		t.Fatalf("failed to get stats with %+v: %v", filters, err)
+	}
+	assertStats(t, stats, 2, 10, 25, 17.5)
+	assertValueCounts(t, "city", stats.CityCounts, "Moscow:2")
+	assertValueCounts(
+		t, "handover year", stats.HandoverYearCounts, "2019:1", "2020:1")
+
+	// Statistics of no buildings have no floors count statistics.
+	filters = &logic.BuildingFilters{
+		City: &logic.Filter[string]{Eq: ptr("Kazan")},
+		HandoverYear: &logic.Filter[uint64]{Eq: ptr[uint64](2019)},
+	}
+	stats, err = repository.GetStats(ctx, filters)
+	if err != nil {
+		t.Fatalf("failed to get stats with %+v: %v", filters, err)
+	}
+	if stats.Count != 0 {
+		t.Errorf("got count %d, want 0", stats.Count)
+	}
+	if stats.MinFloorsCount != nil ||
+			stats.MaxFloorsCount != nil ||
+			stats.AvgFloorsCount != nil {
+		t.Errorf("got floors count statistics of no buildings %+v", stats)
+	}
+	assertValueCounts(t, "city", stats.CityCounts)
+	assertValueCounts(t, "handover year", stats.HandoverYearCounts)
+}
+
+// Checks count and floors count statistics.
+func assertStats(
+		t *testing.T,
+		stats *logic.BuildingStats,
+		count uint64,
+		minFloorsCount uint64,
+		maxFloorsCount uint64,
+		avgFloorsCount float64) {
+	t.Helper()
+
+	if stats.Count != count {
+		t.Errorf("got count %d, want %d", stats.Count, count)
+	}
+	if stats.MinFloorsCount == nil || *stats.MinFloorsCount != minFloorsCount {
+		t.Errorf(
+			"got min floors count %v, want %d",
+			stats.MinFloorsCount,
+			minFloorsCount)
+	}
+	if stats.MaxFloorsCount == nil || *stats.MaxFloorsCount != maxFloorsCount {
+		t.Errorf(
+			"got max floors count %v, want %d",
+			stats.MaxFloorsCount,
+			maxFloorsCount)
+	}
+	if stats.AvgFloorsCount == nil || *stats.AvgFloorsCount != avgFloorsCount {
+		t.Errorf(
+			"got avg floors count %v, want %v",
+			stats.AvgFloorsCount,
+			avgFloorsCount)
+	}
+}
+
+// Checks that value counts are equal to the wanted ones in "value:count" form
+// in the same order.
+func assertValueCounts[T logic.FilterValue](
+		t *testing.T,
+		name string,
+		counts []*logic.ValueCount[T],
+		want ...string) {
+	t.Helper()
+
+	got := make([]string, 0, len(counts))
+	for _, count := range counts {
+		got = append(got, fmt.Sprintf("%v:%d", count.Value, count.Count))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %s counts %q, want %q", name, got, want)
+	}
+}
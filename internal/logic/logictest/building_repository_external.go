@@ -0,0 +1,193 @@
+package logictest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Tests that upsert inserts building with new external identifier, updates the
+// one with the same external identifier and distinguishes sources.
+func testUpsert(t *testing.T, repository logic.BuildingRepository) {
+	externalId := domain.NewExternalId("partner", "42")
+
+	// Building with new external identifier must be inserted.
+	inserted := upsertBuilding(t, repository, externalId, fixtureInfos[0], true)
+
+	// Building with the same external identifier must be updated in place.
+	updated := upsertBuilding(t, repository, externalId, fixtureInfos[1], false)
+	if updated.Id != inserted.Id {
+		t.Errorf("updated building %d, want %d", updated.Id, inserted.Id)
+	}
+	assertStored(t, repository, inserted.Id, fixtureInfos[1])
+
+	// The same identifier of another source is another building.
+	otherId := domain.NewExternalId("crm", "42")
+	other := upsertBuilding(t, repository, otherId, fixtureInfos[2], true)
+	if other.Id == inserted.Id {
+		t.Errorf("upserted building of another source into %d", inserted.Id)
+	}
+	assertCount(t, repository, &logic.BuildingFilters{}, 2)
+
+	// Rejected building must not change the stored one.
+	info := domain.NewBuildingInfo("Floors", "Max", 2020, math.MaxUint64)
+	_, _, err := repository.Upsert(
+		context.Background(), logic.NewExternalBuildingInfo(externalId, info))
+	if !errors.Is(err, logic.ErrValidation) {
+		t.Errorf(
+			"upserted %+v with error %v, want %v",
+			info,
+			err,
+			logic.ErrValidation)
+	}
+	assertStored(t, repository, inserted.Id, fixtureInfos[1])
+}
+
+// Tests that batch upsert counts inserted and updated buildings, including
+// repeated external identifiers, and that rejected building aborts the batch.
+func testUpsertBatch(t *testing.T, repository logic.BuildingRepository) {
+	ctx := context.Background()
+	externalId := domain.NewExternalId("partner", "1")
+	existing := upsertBuilding(t, repository, externalId, fixtureInfos[0], true)
+
+	// Batch with rejected building must upsert nothing.
+	infos := []*logic.ExternalBuildingInfo{
+		logic.NewExternalBuildingInfo(
+			domain.NewExternalId("partner", "2"), copyInfo(fixtureInfos[1])),
+		logic.NewExternalBuildingInfo(
+			domain.NewExternalId("partner", "3"),
+			domain.NewBuildingInfo("Floors", "Max", 2020, math.MaxUint64)),
+	}
+	if _, err := repository.UpsertBatch(ctx, infos); !errors.Is(
+			err, logic.ErrValidation) {
+		t.Errorf(
+			"upserted batch with error %v, want %v", err, logic.ErrValidation)
+	}
+	assertCount(t, repository, &logic.BuildingFilters{}, 1)
+
+	// Batch must update existing building, insert new one and update it again
+	// when its external identifier is repeated.
+	infos = []*logic.ExternalBuildingInfo{
+		logic.NewExternalBuildingInfo(externalId, copyInfo(fixtureInfos[2])),
+		logic.NewExternalBuildingInfo(
+			domain.NewExternalId("partner", "2"), copyInfo(fixtureInfos[3])),
+		logic.NewExternalBuildingInfo(
+			domain.NewExternalId("partner", "2"), copyInfo(fixtureInfos[4])),
+	}
+	counts, err := repository.UpsertBatch(ctx, infos)
+	if err != nil {
+		t.Fatalf("failed to upsert batch: %v", err)
+	}
+	want := logic.BuildingUpsertCounts{Inserted: 1, Updated: 2}
+	if *counts != want {
+		t.Errorf("got counts %+v, want %+v", *counts, want)
+	}
+	assertStored(t, repository, existing.Id, fixtureInfos[2])
+	assertNames(
+		t,
+		getAll(t, repository, sortById(&logic.BuildingFilters{})),
+		fixtureInfos[2].Name,
+		fixtureInfos[4].Name)
+}
+
+// Tests that merge deletes duplicate, moves its external identifier only to
+// the building without one and does nothing if one of the buildings is
+// missing.
+func testMerge(t *testing.T, repository logic.BuildingRepository) {
+	ctx := context.Background()
+	externalId := domain.NewExternalId("partner", "1")
+	building := insertBuilding(t, repository, fixtureInfos[0])
+	duplicate := upsertBuilding(
+		t, repository, externalId, fixtureInfos[1], true)
+
+	// Missing building or duplicate must change nothing.
+	for _, ids := range [][2]int64{
+		{building.Id, duplicate.Id + 1000},
+		{building.Id + 1000, duplicate.Id},
+	} {
+		_, err := repository.Merge(ctx, ids[0], ids[1])
+		if !errors.Is(err, logic.ErrBuildingNotFound) {
+			t.Errorf(
+				"merged %d into %d with error %v, want %v",
+				ids[1],
+				ids[0],
+				err,
+				logic.ErrBuildingNotFound)
+		}
+	}
+	assertCount(t, repository, &logic.BuildingFilters{}, 2)
+
+	// Duplicate must be deleted and its external identifier moved.
+	merged, err := repository.Merge(ctx, building.Id, duplicate.Id)
+	if err != nil {
+		t.Fatalf(
+			"failed to merge %d into %d: %v", duplicate.Id, building.Id, err)
+	}
+	if merged.Id != building.Id || !equalInfos(merged.Info, fixtureInfos[0]) {
+		t.Errorf("merged into %+v, want %+v", merged, building)
+	}
+	assertExternalId(t, merged, externalId)
+	assertCount(t, repository, &logic.BuildingFilters{}, 1)
+
+	// Upsert by moved external identifier must update the building.
+	upserted := upsertBuilding(
+		t, repository, externalId, fixtureInfos[2], false)
+	if upserted.Id != building.Id {
+		t.Errorf("upserted building %d, want %d", upserted.Id, building.Id)
+	}
+
+	// Building with external identifier must keep it.
+	otherId := domain.NewExternalId("partner", "2")
+	duplicate = upsertBuilding(t, repository, otherId, fixtureInfos[3], true)
+	merged, err = repository.Merge(ctx, building.Id, duplicate.Id)
+	if err != nil {
+		t.Fatalf(
+			"failed to merge %d into %d: %v", duplicate.Id, building.Id, err)
+	}
+	assertExternalId(t, merged, externalId)
+	assertCount(t, repository, &logic.BuildingFilters{}, 1)
+}
+
+// Checks that building has wanted external identifier.
+func assertExternalId(
+		t *testing.T, building *domain.Building, want *domain.ExternalId) {
+	t.Helper()
+
+	if building.ExternalId == nil || *building.ExternalId != *want {
+		t.Errorf("got external id %+v, want %+v", building.ExternalId, want)
+	}
+}
+
+// Upserts building with passed external identifier and information and checks
+// that it is returned unchanged and inserted as wanted or fails the test.
+func upsertBuilding(
+		t *testing.T,
+		repository logic.BuildingRepository,
+		externalId *domain.ExternalId,
+		info *domain.BuildingInfo,
+		wantInserted bool) *domain.Building {
+	t.Helper()
+
+	externalInfo := logic.NewExternalBuildingInfo(externalId, copyInfo(info))
+	building, inserted, err := repository.Upsert(
+		context.Background(), externalInfo)
+	if err != nil {
+		t.Fatalf("failed to upsert %+v: %v", info, err)
+	}
+	if inserted != wantInserted {
+		t.Errorf(
+			"upserted %+v with inserted %t, want %t",
+			info,
+			inserted,
+			wantInserted)
+	}
+	if !equalInfos(building.Info, info) {
+		t.Errorf("upserted %+v, want %+v", building.Info, info)
+	}
+	assertExternalId(t, building, externalId)
+	return building
+}
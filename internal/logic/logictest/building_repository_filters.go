@@ -0,0 +1,416 @@
+package logictest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Case of the filters test with names of the fixture buildings which must be
+// selected in the order of insertion.
+type filtersCase struct {
+	name string
+	filters *logic.BuildingFilters
+	want []string
+}
+
+// Cases of the filters test. They cover each operator on each field, their
+// combinations, search and values that do not fit the storage.
+var filtersCases = []filtersCase{
+	{
+		name: "no filters",
+		filters: &logic.BuildingFilters{},
+		want: []string{
+			"Aurora", "Birch Grove", "Cedar Court", "Dune Tower", "Elm House",
+		},
+	},
+
+	// City filters.
+	{
+		name: "city eq",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{Eq: ptr("Moscow")},
+		},
+		want: []string{"Aurora", "Cedar Court"},
+	},
+	{
+		name: "city eq missing",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{Eq: ptr("moscow")},
+		},
+		want: []string{},
+	},
+	{
+		name: "city ne",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{Ne: ptr("Moscow")},
+		},
+		want: []string{"Birch Grove", "Dune Tower", "Elm House"},
+	},
+	{
+		name: "city gt",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{Gt: ptr("Kazan")},
+		},
+		want: []string{"Aurora", "Cedar Court", "Dune Tower"},
+	},
+	{
+		name: "city gte and lt",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{Gte: ptr("Kazan"), Lt: ptr("Sochi")},
+		},
+		want: []string{"Aurora", "Birch Grove", "Cedar Court", "Elm House"},
+	},
+	{
+		name: "city lte",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{Lte: ptr("Kazan")},
+		},
+		want: []string{"Birch Grove", "Elm House"},
+	},
+	{
+		name: "city in",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{In: []string{"Kazan", "Sochi", "Tver"}},
+		},
+		want: []string{"Birch Grove", "Dune Tower", "Elm House"},
+	},
+	{
+		name: "city in empty",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{In: []string{}},
+		},
+		want: []string{},
+	},
+	{
+		name: "city not in",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{NotIn: []string{"Kazan"}},
+		},
+		want: []string{"Aurora", "Cedar Court", "Dune Tower"},
+	},
+	{
+		name: "city not in empty",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{NotIn: []string{}},
+		},
+		want: []string{
+			"Aurora", "Birch Grove", "Cedar Court", "Dune Tower", "Elm House",
+		},
+	},
+
+	// Handover year filters.
+	{
+		name: "handover year eq",
+		filters: &logic.BuildingFilters{
+			HandoverYear: &logic.Filter[uint64]{Eq: ptr[uint64](2021)},
+		},
+		want: []string{"Birch Grove", "Dune Tower"},
+	},
+	{
+		name: "handover year ne",
+		filters: &logic.BuildingFilters{
+			HandoverYear: &logic.Filter[uint64]{Ne: ptr[uint64](2021)},
+		},
+		want: []string{"Aurora", "Cedar Court", "Elm House"},
+	},
+	{
+		name: "handover year gt and lte",
+		filters: &logic.BuildingFilters{
+			HandoverYear: &logic.Filter[uint64]{
+				Gt: ptr[uint64](2019),
+				Lte: ptr[uint64](2020),
+			},
+		},
+		want: []string{"Aurora", "Elm House"},
+	},
+	{
+		name: "handover year gte",
+		filters: &logic.BuildingFilters{
+			HandoverYear: &logic.Filter[uint64]{Gte: ptr[uint64](2021)},
+		},
+		want: []string{"Birch Grove", "Dune Tower"},
+	},
+	{
+		name: "handover year lt",
+		filters: &logic.BuildingFilters{
+			HandoverYear: &logic.Filter[uint64]{Lt: ptr[uint64](2020)},
+		},
+		want: []string{"Cedar Court"},
+	},
+	{
+		name: "handover year in",
+		filters: &logic.BuildingFilters{
+			HandoverYear: &logic.Filter[uint64]{In: []uint64{2019, 2021}},
+		},
+		want: []string{"Birch Grove", "Cedar Court", "Dune Tower"},
+	},
+	{
+		name: "handover year not in",
+		filters: &logic.BuildingFilters{
+			HandoverYear: &logic.Filter[uint64]{NotIn: []uint64{2019, 2021}},
+		},
+		want: []string{"Aurora", "Elm House"},
+	},
+
+	// Floors count filters, including zero floors.
+	{
+		name: "floors count eq zero",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{Eq: ptr[uint64](0)},
+		},
+		want: []string{"Dune Tower"},
+	},
+	{
+		name: "floors count ne",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{Ne: ptr[uint64](10)},
+		},
+		want: []string{"Birch Grove", "Cedar Court", "Dune Tower"},
+	},
+	{
+		name: "floors count gt zero",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{Gt: ptr[uint64](0)},
+		},
+		want: []string{"Aurora", "Birch Grove", "Cedar Court", "Elm House"},
+	},
+	{
+		name: "floors count gte zero",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{Gte: ptr[uint64](0)},
+		},
+		want: []string{
+			"Aurora", "Birch Grove", "Cedar Court", "Dune Tower", "Elm House",
+		},
+	},
+	{
+		name: "floors count lt",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{Lt: ptr[uint64](10)},
+		},
+		want: []string{"Birch Grove", "Dune Tower"},
+	},
+	{
+		name: "floors count lte zero",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{Lte: ptr[uint64](0)},
+		},
+		want: []string{"Dune Tower"},
+	},
+	{
+		name: "floors count in",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{In: []uint64{0, 25}},
+		},
+		want: []string{"Cedar Court", "Dune Tower"},
+	},
+	{
+		name: "floors count not in",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{NotIn: []uint64{0}},
+		},
+		want: []string{"Aurora", "Birch Grove", "Cedar Court", "Elm House"},
+	},
+	{
+		name: "floors count contradiction",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{
+				Gt: ptr[uint64](10),
+				Lt: ptr[uint64](10),
+			},
+		},
+		want: []string{},
+	},
+
+	// Values which do not fit the storage.
+	{
+		name: "floors count eq max",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{Eq: ptr[uint64](math.MaxUint64)},
+		},
+		want: []string{},
+	},
+	{
+		name: "floors count ne max",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{Ne: ptr[uint64](math.MaxUint64)},
+		},
+		want: []string{
+			"Aurora", "Birch Grove", "Cedar Court", "Dune Tower", "Elm House",
+		},
+	},
+	{
+		name: "floors count gt int32",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{Gt: ptr[uint64](math.MaxInt32)},
+		},
+		want: []string{},
+	},
+	{
+		name: "floors count lt max",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{Lt: ptr[uint64](math.MaxUint64)},
+		},
+		want: []string{
+			"Aurora", "Birch Grove", "Cedar Court", "Dune Tower", "Elm House",
+		},
+	},
+	{
+		name: "floors count lte int64",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{Lte: ptr[uint64](math.MaxInt64)},
+		},
+		want: []string{
+			"Aurora", "Birch Grove", "Cedar Court", "Dune Tower", "Elm House",
+		},
+	},
+	{
+		name: "handover year gte max",
+		filters: &logic.BuildingFilters{
+			HandoverYear: &logic.Filter[uint64]{
+				Gte: ptr[uint64](math.MaxUint64),
+			},
+		},
+		want: []string{},
+	},
+	{
+		name: "handover year in with max",
+		filters: &logic.BuildingFilters{
+			HandoverYear: &logic.Filter[uint64]{
+				In: []uint64{math.MaxUint64, math.MaxInt32 + 1, 2019},
+			},
+		},
+		want: []string{"Cedar Court"},
+	},
+	{
+		name: "floors count not in max",
+		filters: &logic.BuildingFilters{
+			FloorsCount: &logic.Filter[uint64]{
+				NotIn: []uint64{math.MaxUint64, 0},
+			},
+		},
+		want: []string{"Aurora", "Birch Grove", "Cedar Court", "Elm House"},
+	},
+
+	// Combinations of the filters.
+	{
+		name: "city and handover year",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{Eq: ptr("Kazan")},
+			HandoverYear: &logic.Filter[uint64]{Eq: ptr[uint64](2020)},
+		},
+		want: []string{"Elm House"},
+	},
+	{
+		name: "city and floors count",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{In: []string{"Moscow", "Sochi"}},
+			FloorsCount: &logic.Filter[uint64]{Lte: ptr[uint64](10)},
+		},
+		want: []string{"Aurora", "Dune Tower"},
+	},
+	{
+		name: "handover year and floors count",
+		filters: &logic.BuildingFilters{
+			HandoverYear: &logic.Filter[uint64]{Gte: ptr[uint64](2020)},
+			FloorsCount: &logic.Filter[uint64]{Gt: ptr[uint64](5)},
+		},
+		want: []string{"Aurora", "Elm House"},
+	},
+	{
+		name: "all fields",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{Ne: ptr("Sochi")},
+			HandoverYear: &logic.Filter[uint64]{NotIn: []uint64{2019}},
+			FloorsCount: &logic.Filter[uint64]{Gte: ptr[uint64](5)},
+		},
+		want: []string{"Aurora", "Birch Grove", "Elm House"},
+	},
+	{
+		name: "all fields without match",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{Eq: ptr("Moscow")},
+			HandoverYear: &logic.Filter[uint64]{Eq: ptr[uint64](2021)},
+			FloorsCount: &logic.Filter[uint64]{Eq: ptr[uint64](0)},
+		},
+		want: []string{},
+	},
+
+	// Search alone and with other filters.
+	{
+		name: "search word prefix",
+		filters: &logic.BuildingFilters{
+			Search: logic.NewBuildingSearch("aur", false),
+		},
+		want: []string{"Aurora"},
+	},
+	{
+		name: "search all words",
+		filters: &logic.BuildingFilters{
+			Search: logic.NewBuildingSearch("Cedar Court", false),
+		},
+		want: []string{"Cedar Court"},
+	},
+	{
+		name: "search city excluded",
+		filters: &logic.BuildingFilters{
+			Search: logic.NewBuildingSearch("kazan", false),
+		},
+		want: []string{},
+	},
+	{
+		name: "search city included",
+		filters: &logic.BuildingFilters{
+			Search: logic.NewBuildingSearch("kazan", true),
+		},
+		want: []string{"Birch Grove", "Elm House"},
+	},
+	{
+		name: "search without match",
+		filters: &logic.BuildingFilters{
+			Search: logic.NewBuildingSearch("xyzzy", true),
+		},
+		want: []string{},
+	},
+	{
+		name: "search and city",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{Eq: ptr("Sochi")},
+			Search: logic.NewBuildingSearch("dune", false),
+		},
+		want: []string{"Dune Tower"},
+	},
+	{
+		name: "search and other city",
+		filters: &logic.BuildingFilters{
+			City: &logic.Filter[string]{Eq: ptr("Moscow")},
+			Search: logic.NewBuildingSearch("dune", false),
+		},
+		want: []string{},
+	},
+	{
+		name: "search and numeric filters",
+		filters: &logic.BuildingFilters{
+			HandoverYear: &logic.Filter[uint64]{Eq: ptr[uint64](2020)},
+			FloorsCount: &logic.Filter[uint64]{Eq: ptr[uint64](10)},
+			Search: logic.NewBuildingSearch("kazan", true),
+		},
+		want: []string{"Elm House"},
+	},
+}
+
+// Tests that each filter selects the same buildings from GetAll and Count.
+func testFilters(t *testing.T, repository logic.BuildingRepository) {
+	insertFixtures(t, repository)
+
+	for _, c := range filtersCases {
+		t.Run(c.name, func(t *testing.T) {
+			filters := *c.filters
+			buildings := getAll(t, repository, sortById(&filters))
+			assertNames(t, buildings, c.want...)
+			assertCount(t, repository, &filters, uint64(len(c.want)))
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package logictest
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Case of the sort test with names of the fixture buildings in the wanted
+// order.
+type sortCase struct {
+	name string
+	sort []*logic.BuildingSortKey
+	want []string
+}
+
+// Cases of the sort test. Buildings with equal keys must be ordered by
+// identifier.
+var sortCases = []sortCase{
+	{
+		name: "default",
+		sort: nil,
+		want: []string{
+			"Aurora", "Birch Grove", "Cedar Court", "Dune Tower", "Elm House",
+		},
+	},
+	{
+		name: "id descending",
+		sort: []*logic.BuildingSortKey{
+			logic.NewBuildingSortKey(logic.BuildingFieldId, true),
+		},
+		want: []string{
+			"Elm House", "Dune Tower", "Cedar Court", "Birch Grove", "Aurora",
+		},
+	},
+	{
+		name: "name",
+		sort: []*logic.BuildingSortKey{
+			logic.NewBuildingSortKey(logic.BuildingFieldName, false),
+		},
+		want: []string{
+			"Aurora", "Birch Grove", "Cedar Court", "Dune Tower", "Elm House",
+		},
+	},
+	{
+		name: "name descending",
+		sort: []*logic.BuildingSortKey{
+			logic.NewBuildingSortKey(logic.BuildingFieldName, true),
+		},
+		want: []string{
+			"Elm House", "Dune Tower", "Cedar Court", "Birch Grove", "Aurora",
+		},
+	},
+	{
+		name: "floors count descending with ties",
+		sort: []*logic.BuildingSortKey{
+			logic.NewBuildingSortKey(logic.BuildingFieldFloorsCount, true),
+		},
+		want: []string{
+			"Cedar Court", "Aurora", "Elm House", "Birch Grove", "Dune Tower",
+		},
+	},
+	{
+		name: "handover year and name descending",
+		sort: []*logic.BuildingSortKey{
+			logic.NewBuildingSortKey(logic.BuildingFieldHandoverYear, false),
+			logic.NewBuildingSortKey(logic.BuildingFieldName, true),
+		},
+		want: []string{
+			"Cedar Court", "Elm House", "Aurora", "Dune Tower", "Birch Grove",
+		},
+	},
+	{
+		name: "city and floors count",
+		sort: []*logic.BuildingSortKey{
+			logic.NewBuildingSortKey(logic.BuildingFieldCity, false),
+			logic.NewBuildingSortKey(logic.BuildingFieldFloorsCount, false),
+		},
+		want: []string{
+			"Birch Grove", "Elm House", "Aurora", "Cedar Court", "Dune Tower",
+		},
+	},
+	{
+		name: "city descending and id descending",
+		sort: []*logic.BuildingSortKey{
+			logic.NewBuildingSortKey(logic.BuildingFieldCity, true),
+			logic.NewBuildingSortKey(logic.BuildingFieldId, true),
+		},
+		want: []string{
+			"Dune Tower", "Cedar Court", "Aurora", "Elm House", "Birch Grove",
+		},
+	},
+}
+
+// Tests that buildings are ordered by sort keys and then by identifier.
+func testSort(t *testing.T, repository logic.BuildingRepository) {
+	insertFixtures(t, repository)
+
+	for _, c := range sortCases {
+		t.Run(c.name, func(t *testing.T) {
+			filters := (&logic.BuildingFilters{}).WithSort(c.sort)
+			assertNames(t, getAll(t, repository, filters), c.want...)
+		})
+	}
+}
+
+// Tests that limit and offset select a window of the ordered buildings.
+func testOffsetPagination(
+		t *testing.T, repository logic.BuildingRepository) {
+	insertFixtures(t, repository)
+	sort := []*logic.BuildingSortKey{
+		logic.NewBuildingSortKey(logic.BuildingFieldName, true),
+	}
+
+	cases := []struct {
+		name string
+		limit *uint64
+		offset *uint64
+		want []string
+	}{
+		{"limit", ptr[uint64](2), nil, []string{"Elm House", "Dune Tower"}},
+		{"offset", nil, ptr[uint64](3), []string{"Birch Grove", "Aurora"}},
+		{
+			"limit and offset",
+			ptr[uint64](2),
+			ptr[uint64](1),
+			[]string{"Dune Tower", "Cedar Court"},
+		},
+		{"zero limit", ptr[uint64](0), nil, []string{}},
+		{"offset beyond", ptr[uint64](2), ptr[uint64](5), []string{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			filters := (&logic.BuildingFilters{}).
+				WithSort(sort).
+				WithPagination(c.limit, c.offset, nil)
+			assertNames(t, getAll(t, repository, filters), c.want...)
+
+			// Count must ignore pagination.
+			assertCount(t, repository, filters, uint64(len(fixtureInfos)))
+		})
+	}
+}
+
+// Tests that walking pages with cursor of the last building of each page
+// selects each building exactly once in the order of the sort keys.
+func testCursorPagination(
+		t *testing.T, repository logic.BuildingRepository) {
+	insertFixtures(t, repository)
+
+	for _, c := range sortCases {
+		t.Run(c.name, func(t *testing.T) {
+			filters := (&logic.BuildingFilters{}).WithSort(c.sort)
+			assertNames(t, walkPages(t, repository, filters, 2), c.want...)
+		})
+	}
+
+	// Cursor must work together with filters.
+	filters := (&logic.BuildingFilters{
+		City: &logic.Filter[string]{Ne: ptr("Sochi")},
+	}).WithSort(sortCases[4].sort)
+	want := []string{"Cedar Court", "Aurora", "Elm House", "Birch Grove"}
+	assertNames(t, walkPages(t, repository, filters, 3), want...)
+}
+
+// Tests that walking pages of search results ordered by relevance selects
+// each matching building exactly once in the same order as a single page.
+func testSearchCursorPagination(
+		t *testing.T, repository logic.BuildingRepository) {
+	insertFixtures(t, repository)
+	filters := &logic.BuildingFilters{
+		Search: logic.NewBuildingSearch("moscow", true),
+	}
+
+	// Both buildings in Moscow must match in any order.
+	want := getNames(getAll(t, repository, filters))
+	matched := slices.Clone(want)
+	slices.Sort(matched)
+	if !slices.Equal(matched, []string{"Aurora", "Cedar Court"}) {
+		t.Fatalf("got buildings %q, want Aurora and Cedar Court", matched)
+	}
+
+	assertNames(t, walkPages(t, repository, filters, 1), want...)
+}
+
+// Gets all buildings selected by passed filters page by page, passing cursor
+// of the last building of each page to get the next one.
+func walkPages(
+		t *testing.T,
+		repository logic.BuildingRepository,
+		filters *logic.BuildingFilters,
+		limit uint64) []*domain.Building {
+	t.Helper()
+
+	var buildings []*domain.Building
+	var cursor *logic.BuildingCursor
+	for page := 0; ; page++ {
+		// Guard against cursors that do not move forward.
+		if page > len(fixtureInfos) {
+			t.Fatalf("pagination does not stop after %d pages", page)
+		}
+
+		pageFilters := *filters
+		pageFilters.WithPagination(&limit, nil, cursor)
+		pageBuildings := getAll(t, repository, &pageFilters)
+		buildings = append(buildings, pageBuildings...)
+		if uint64(len(pageBuildings)) < limit {
+			return buildings
+		}
+		cursor = logic.NewBuildingCursor(pageBuildings[len(pageBuildings) - 1])
+	}
+}
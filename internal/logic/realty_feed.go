@@ -0,0 +1,157 @@
+package logic
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+)
+
+// ErrInvalidRealtyFeed is returned if realty feed can not be parsed.
+var ErrInvalidRealtyFeed = NewError(
+		ErrInvalidArgument, "invalid_realty_feed", "invalid realty feed")
+
+// Name of the root element of the realty feed.
+const realtyFeedRootName = "realty-feed"
+
+// Offer of the realty feed. Only elements describing the building are parsed.
+// Feed generation date and offer type, property type, category and creation
+// date are not parsed, because buildings are made of complexes of all offers
+// regardless of them, and location is parsed only for the city, because
+// catalog does not store addresses.
+type realtyFeedOffer struct {
+	BuildingId string   `xml:"yandex-building-id"`
+	BuildingName string `xml:"building-name"`
+	Locality string     `xml:"location>locality-name"`
+	BuiltYear uint64    `xml:"built-year"`
+	FloorsTotal uint64  `xml:"floors-total"`
+}
+
+// Returns identifier of the complex the offer belongs to. Yandex identifier is
+// used if it is set. Otherwise, complex is identified by its name and city,
+// which are quoted, so different names and cities can not form the same
+// identifier and it can not be confused with Yandex one.
+func (offer *realtyFeedOffer) complexId() string {
+	if offer.BuildingId != "" {
+		return offer.BuildingId
+	}
+	return fmt.Sprintf(
+		"%q/%q",
+		strings.ToLower(offer.BuildingName),
+		strings.ToLower(offer.Locality))
+}
+
+// RealtyFeed is a result of parsing Yandex.Realty style feed. Offers are
+// grouped to complexes, each of which becomes a building.
+type RealtyFeed struct {
+	Infos []*ExternalBuildingInfo
+
+	// Descriptions of complexes that are skipped, because they do not have
+	// required information or it is invalid.
+	Skipped []string
+}
+
+// ParseRealtyFeed parses Yandex.Realty style feed of passed source or returns an
+// error. Complexes of the feed are identified by "yandex-building-id" or by
+// their names and cities if it is absent. Handover year and floors count of
+// the complex are maximum ones of its offers.
+func ParseRealtyFeed(reader io.Reader, source string) (*RealtyFeed, error) {
+	decoder := xml.NewDecoder(reader)
+
+	// Complexes by their identifiers in the order of appearance.
+	var ids []string
+	complexes := make(map[string]*domain.BuildingInfo)
+
+	root := true
+	for {
+		// Try to read the next token.
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRealtyFeed, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		// Check that feed has expected root element.
+		if root {
+			if start.Name.Local != realtyFeedRootName {
+				return nil, fmt.Errorf(
+					"%w: root element is %s", ErrInvalidRealtyFeed, start.Name.Local)
+			}
+			root = false
+			continue
+		}
+		if start.Name.Local != "offer" {
+			continue
+		}
+
+		// Try to decode offer.
+		var offer realtyFeedOffer
+		if err := decoder.DecodeElement(&offer, &start); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRealtyFeed, err)
+		}
+		offer.BuildingName = strings.TrimSpace(offer.BuildingName)
+		offer.Locality = strings.TrimSpace(offer.Locality)
+
+		// Merge offer into its complex.
+		id := offer.complexId()
+		info, ok := complexes[id]
+		if !ok {
+			info = domain.NewBuildingInfo(
+				offer.BuildingName, offer.Locality, 0, 0)
+			complexes[id] = info
+			ids = append(ids, id)
+		}
+		info.HandoverYear = max(info.HandoverYear, offer.BuiltYear)
+		info.FloorsCount = max(info.FloorsCount, offer.FloorsTotal)
+	}
+	if root {
+		return nil, fmt.Errorf("%w: feed is empty", ErrInvalidRealtyFeed)
+	}
+
+	// Collect complexes with all required information.
+	feed := &RealtyFeed{}
+	for _, id := range ids {
+		info := complexes[id]
+		if missing := getMissingRealtyFeedFields(info); len(missing) > 0 {
+			feed.Skipped = append(feed.Skipped, fmt.Sprintf(
+				"complex %q: missing %s", id, strings.Join(missing, ", ")))
+			continue
+		}
+		if err := prepareBuildingInfo(info); err != nil {
+			feed.Skipped = append(
+				feed.Skipped, fmt.Sprintf("complex %q: %v", id, err))
+			continue
+		}
+
+		externalId := domain.NewExternalId(source, id)
+		feed.Infos = append(feed.Infos, NewExternalBuildingInfo(externalId, info))
+	}
+
+	return feed, nil
+}
+
+// Returns names of feed elements that are required, but missing in the
+// complex information.
+func getMissingRealtyFeedFields(info *domain.BuildingInfo) []string {
+	var missing []string
+	if info.Name == "" {
+		missing = append(missing, "building-name")
+	}
+	if info.City == "" {
+		missing = append(missing, "location/locality-name")
+	}
+	if info.HandoverYear == 0 {
+		missing = append(missing, "built-year")
+	}
+	if info.FloorsCount == 0 {
+		missing = append(missing, "floors-total")
+	}
+	return missing
+}
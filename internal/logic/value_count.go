@@ -0,0 +1,16 @@
+package logic
+
+// ValueCount is a distinct value of the field with the count of entities that
+// have it.
+type ValueCount[T FilterValue] struct {
+	Value T
+	Count uint64
+}
+
+// NewValueCount creates a new instance of value count.
+func NewValueCount[T FilterValue](value T, count uint64) *ValueCount[T] {
+	return &ValueCount[T]{
+		Value: value,
+		Count: count,
+	}
+}
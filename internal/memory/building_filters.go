@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"slices"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Checks that building matches filter parameters, ignoring pagination. Has the
+// same semantics as filter conditions of the database queries.
+func matchesFilters(
+		building *domain.Building, filters *logic.BuildingFilters) bool {
+	// Check city filter if parameter is not nil.
+	if filters.City != nil && !matchesFilter(building.Info.City, filters.City) {
+		return false
+	}
+
+	// Check handover year filter if parameter is not nil.
+	if filters.HandoverYear != nil &&
+			!matchesFilter(building.Info.HandoverYear, filters.HandoverYear) {
+		return false
+	}
+
+	// Check floors count filter if parameter is not nil.
+	if filters.FloorsCount != nil &&
+			!matchesFilter(building.Info.FloorsCount, filters.FloorsCount) {
+		return false
+	}
+
+	// Check search if parameter is not nil.
+	if filters.Search != nil && !matchesSearch(building, filters.Search) {
+		return false
+	}
+
+	return true
+}
+
+// Checks that value meets all set conditions of the filter. Empty In slice
+// matches nothing and empty NotIn slice matches everything.
+func matchesFilter[T logic.FilterValue](value T, filter *logic.Filter[T]) bool {
+	// Check comparison conditions if their values are set.
+	if filter.Eq != nil && value != *filter.Eq {
+		return false
+	}
+	if filter.Ne != nil && value == *filter.Ne {
+		return false
+	}
+	if filter.Gt != nil && value <= *filter.Gt {
+		return false
+	}
+	if filter.Gte != nil && value < *filter.Gte {
+		return false
+	}
+	if filter.Lt != nil && value >= *filter.Lt {
+		return false
+	}
+	if filter.Lte != nil && value > *filter.Lte {
+		return false
+	}
+
+	// Check set membership conditions if their values are set.
+	if filter.In != nil && !slices.Contains(filter.In, value) {
+		return false
+	}
+	if filter.NotIn != nil && slices.Contains(filter.NotIn, value) {
+		return false
+	}
+
+	return true
+}
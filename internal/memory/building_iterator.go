@@ -0,0 +1,39 @@
+package memory
+
+import "github.com/rylenko/leadgen-market-task/internal/domain"
+
+// Iterator over a snapshot of the buildings, which is taken when the iterator
+// is opened, so changes of the repository do not affect it.
+type buildingSliceIterator struct {
+	buildings []*domain.Building
+	// Index of the current building, which is -1 before the first one.
+	index int
+}
+
+func (iterator *buildingSliceIterator) Next() bool {
+	if iterator.index + 1 >= len(iterator.buildings) {
+		iterator.index = len(iterator.buildings)
+		return false
+	}
+	iterator.index++
+	return true
+}
+
+func (iterator *buildingSliceIterator) Building() *domain.Building {
+	return iterator.buildings[iterator.index]
+}
+
+func (iterator *buildingSliceIterator) Err() error {
+	return nil
+}
+
+func (iterator *buildingSliceIterator) Close() {
+	iterator.buildings = nil
+	iterator.index = 0
+}
+
+// Creates a new iterator over passed buildings.
+func newBuildingSliceIterator(
+		buildings []*domain.Building) *buildingSliceIterator {
+	return &buildingSliceIterator{buildings: buildings, index: -1}
+}
@@ -0,0 +1,621 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Error of the building which numeric values do not fit integer columns of the
+// database. It is returned to keep the same behavior as the database.
+var errNumericValueOutOfRange = logic.NewError(
+		logic.ErrValidation,
+		"numeric_value_out_of_range",
+		"numeric value of the building is out of range")
+
+// BuildingRepositoryImpl is an in-memory implementation of buildings
+// repository. It keeps buildings in a map protected by a mutex, so it is safe
+// for concurrent use. Buildings are lost when the process exits.
+type BuildingRepositoryImpl struct {
+	mutex sync.RWMutex
+	buildings map[int64]*domain.Building
+	// Identifiers of the buildings by their external identifiers.
+	externalIds map[domain.ExternalId]int64
+	// Last assigned identifier. Identifiers are never reused.
+	lastId int64
+}
+
+// Count counts all buildings according to the passed filter parameters,
+// ignoring pagination.
+func (repository *BuildingRepositoryImpl) Count(
+		ctx context.Context, filters *logic.BuildingFilters) (uint64, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var count uint64
+	for _, building := range repository.buildings {
+		if matchesFilters(building, filters) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Delete deletes a building by its identifier. If building does not exist,
+// logic.ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Delete(
+		ctx context.Context, id int64) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	building, ok := repository.buildings[id]
+	if !ok {
+		return logic.ErrBuildingNotFound
+	}
+	repository.delete(building)
+	return nil
+}
+
+// Get gets a building by its identifier. If building does not exist,
+// logic.ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Get(
+		ctx context.Context, id int64) (*domain.Building, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	building, ok := repository.buildings[id]
+	if !ok {
+		return nil, logic.ErrBuildingNotFound
+	}
+	return copyBuilding(building), nil
+}
+
+// GetAll gets all buildings according to the passed filter parameters.
+func (repository *BuildingRepositoryImpl) GetAll(
+		ctx context.Context,
+		filters *logic.BuildingFilters) ([]*domain.Building, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	return repository.selectBuildings(filters), nil
+}
+
+// GetFacets counts buildings by distinct values of each passed field according
+// to the passed filter parameters, ignoring the filter on the field itself,
+// sort keys and pagination.
+func (repository *BuildingRepositoryImpl) GetFacets(
+		ctx context.Context,
+		filters *logic.BuildingFilters,
+		fields []logic.BuildingField) (*logic.BuildingFacets, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var facets logic.BuildingFacets
+	for _, field := range fields {
+		// Select buildings under all filters except the one on the field.
+		fieldFilters := filters.WithoutFilter(field)
+		var buildings []*domain.Building
+		for _, building := range repository.buildings {
+			if matchesFilters(building, fieldFilters) {
+				buildings = append(buildings, building)
+			}
+		}
+
+		// Count values according to the field type.
+		switch field {
+		case logic.BuildingFieldCity:
+			facets.City = countValues(buildings, getCity)
+		case logic.BuildingFieldHandoverYear:
+			facets.HandoverYear = countValues(buildings, getHandoverYear)
+		case logic.BuildingFieldFloorsCount:
+			facets.FloorsCount = countValues(buildings, getFloorsCount)
+		}
+	}
+
+	return &facets, nil
+}
+
+// GetStats gets aggregate statistics of the buildings according to the passed
+// filter parameters, ignoring sort keys and pagination.
+func (repository *BuildingRepositoryImpl) GetStats(
+		ctx context.Context,
+		filters *logic.BuildingFilters) (*logic.BuildingStats, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	// Select buildings according to the filters.
+	var buildings []*domain.Building
+	for _, building := range repository.buildings {
+		if matchesFilters(building, filters) {
+			buildings = append(buildings, building)
+		}
+	}
+
+	// Count buildings by distinct values.
+	stats := &logic.BuildingStats{
+		Count: uint64(len(buildings)),
+		CityCounts: countValues(buildings, getCity),
+		HandoverYearCounts: countValues(buildings, getHandoverYear),
+	}
+	if len(buildings) == 0 {
+		return stats, nil
+	}
+
+	// Compute floors count statistics.
+	var (
+		minFloorsCount uint64 = math.MaxUint64
+		maxFloorsCount uint64
+		sumFloorsCount float64
+	)
+	for _, building := range buildings {
+		minFloorsCount = min(minFloorsCount, building.Info.FloorsCount)
+		maxFloorsCount = max(maxFloorsCount, building.Info.FloorsCount)
+		sumFloorsCount += float64(building.Info.FloorsCount)
+	}
+	avgFloorsCount := sumFloorsCount / float64(len(buildings))
+	stats.MinFloorsCount = &minFloorsCount
+	stats.MaxFloorsCount = &maxFloorsCount
+	stats.AvgFloorsCount = &avgFloorsCount
+
+	return stats, nil
+}
+
+// Init does nothing, because repository is ready after creation.
+func (repository *BuildingRepositoryImpl) Init(ctx context.Context) error {
+	return nil
+}
+
+// Insert inserts a structure to the repository.
+func (repository *BuildingRepositoryImpl) Insert(
+		ctx context.Context,
+		info *domain.BuildingInfo) (*domain.Building, error) {
+	if err := checkNumericRange(info); err != nil {
+		return nil, err
+	}
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	return copyBuilding(repository.insert(info, nil)), nil
+}
+
+// InsertBatch inserts passed buildings and returns result of each of them in
+// the same order. If one of them is rejected, atomic batch is aborted.
+// Otherwise, the rejected building is excluded and the rest are inserted.
+func (repository *BuildingRepositoryImpl) InsertBatch(
+		ctx context.Context,
+		infos []*domain.BuildingInfo,
+		atomic bool) ([]*logic.BuildingBatchResult, error) {
+	results := make([]*logic.BuildingBatchResult, len(infos))
+
+	// Reject buildings which the database would reject.
+	rejected := false
+	for i, info := range infos {
+		if err := checkNumericRange(info); err != nil {
+			results[i] = logic.NewBuildingBatchResult(nil, err)
+			rejected = true
+		}
+	}
+
+	// Abort other buildings of atomic batch.
+	if atomic && rejected {
+		for i, result := range results {
+			if result == nil {
+				results[i] = logic.NewBuildingBatchResult(
+					nil, logic.ErrBuildingBatchAborted)
+			}
+		}
+		return results, nil
+	}
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	// Insert buildings which are not rejected.
+	for i, info := range infos {
+		if results[i] == nil {
+			building := copyBuilding(repository.insert(info, nil))
+			results[i] = logic.NewBuildingBatchResult(building, nil)
+		}
+	}
+
+	return results, nil
+}
+
+// InsertStream inserts all buildings of passed source and returns their count.
+// Source is read completely before insertion, so if it fails or one of the
+// buildings is rejected, nothing is inserted.
+func (repository *BuildingRepositoryImpl) InsertStream(
+		ctx context.Context, source logic.BuildingInfoSource) (uint64, error) {
+	// Try to read all buildings of the source.
+	var infos []*domain.BuildingInfo
+	for source.Next() {
+		info := *source.Info()
+		if err := checkNumericRange(&info); err != nil {
+			return 0, err
+		}
+		infos = append(infos, &info)
+	}
+	if err := source.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read buildings: %w", err)
+	}
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	for _, info := range infos {
+		repository.insert(info, nil)
+	}
+	return uint64(len(infos)), nil
+}
+
+// Iterate opens an iterator over buildings according to the passed filter
+// parameters. Iterator goes over a snapshot of the selected buildings, so the
+// lock is not held while it is advanced.
+func (repository *BuildingRepositoryImpl) Iterate(
+		ctx context.Context,
+		filters *logic.BuildingFilters) (logic.BuildingIterator, error) {
+	buildings, _ := repository.GetAll(ctx, filters)
+	return newBuildingSliceIterator(buildings), nil
+}
+
+// Merge deletes a duplicate building and moves its external identifier to a
+// building with passed identifier if the latter does not have one. If one of
+// the buildings does not exist, logic.ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Merge(
+		ctx context.Context, id, duplicateId int64) (*domain.Building, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	// Check that both buildings exist before changing them.
+	duplicate, ok := repository.buildings[duplicateId]
+	if !ok {
+		return nil, logic.ErrBuildingNotFound
+	}
+	building, ok := repository.buildings[id]
+	if !ok {
+		return nil, logic.ErrBuildingNotFound
+	}
+
+	// Delete duplicate and move its external identifier.
+	repository.delete(duplicate)
+	if building.ExternalId == nil && duplicate.ExternalId != nil {
+		building.ExternalId = duplicate.ExternalId
+		repository.externalIds[*building.ExternalId] = building.Id
+	}
+
+	return copyBuilding(building), nil
+}
+
+// Patch changes only passed fields of a building with passed identifier and
+// returns updated building. If building does not exist,
+// logic.ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Patch(
+		ctx context.Context,
+		id int64,
+		patch *logic.BuildingPatch) (*domain.Building, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	building, ok := repository.buildings[id]
+	if !ok {
+		return nil, logic.ErrBuildingNotFound
+	}
+
+	// Apply patch to a copy of the information, so rejected patch changes
+	// nothing.
+	info := *building.Info
+	if patch.Name != nil {
+		info.Name = *patch.Name
+	}
+	if patch.City != nil {
+		info.City = *patch.City
+	}
+	if patch.HandoverYear != nil {
+		info.HandoverYear = *patch.HandoverYear
+	}
+	if patch.FloorsCount != nil {
+		info.FloorsCount = *patch.FloorsCount
+	}
+	if err := checkNumericRange(&info); err != nil {
+		return nil, err
+	}
+	building.Info = &info
+
+	return copyBuilding(building), nil
+}
+
+// Suggest gets at most limit distinct values of passed field that start with
+// passed prefix, ignoring case. Values are ordered by count of buildings that
+// have them in descending order.
+func (repository *BuildingRepositoryImpl) Suggest(
+		ctx context.Context,
+		field logic.BuildingField,
+		prefix string,
+		limit uint64) ([]*logic.BuildingSuggestion, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	// Select buildings which values of the field start with the prefix.
+	prefix = strings.ToLower(prefix)
+	var buildings []*domain.Building
+	for _, building := range repository.buildings {
+		value := logic.GetBuildingFieldValue(building, field).(string)
+		if strings.HasPrefix(strings.ToLower(value), prefix) {
+			buildings = append(buildings, building)
+		}
+	}
+
+	// Count values and take the most frequent ones.
+	getValue := func(building *domain.Building) string {
+		return logic.GetBuildingFieldValue(building, field).(string)
+	}
+	counts := countValues(buildings, getValue)
+	if uint64(len(counts)) > limit {
+		counts = counts[:limit]
+	}
+
+	suggestions := make([]*logic.BuildingSuggestion, 0, len(counts))
+	for _, count := range counts {
+		suggestions = append(
+			suggestions, logic.NewBuildingSuggestion(count.Value, count.Count))
+	}
+	return suggestions, nil
+}
+
+// Update replaces information of a building with passed identifier. If
+// building does not exist, logic.ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Update(
+		ctx context.Context,
+		id int64,
+		info *domain.BuildingInfo) (*domain.Building, error) {
+	if err := checkNumericRange(info); err != nil {
+		return nil, err
+	}
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	building, ok := repository.buildings[id]
+	if !ok {
+		return nil, logic.ErrBuildingNotFound
+	}
+	infoCopy := *info
+	building.Info = &infoCopy
+
+	return copyBuilding(building), nil
+}
+
+// Upsert inserts passed building or updates the one with the same external
+// identifier and returns it and whether it is inserted.
+func (repository *BuildingRepositoryImpl) Upsert(
+		ctx context.Context,
+		info *logic.ExternalBuildingInfo) (*domain.Building, bool, error) {
+	if err := checkNumericRange(info.Info); err != nil {
+		return nil, false, err
+	}
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	building, inserted := repository.upsert(info)
+	return copyBuilding(building), inserted, nil
+}
+
+// UpsertBatch inserts passed buildings or updates the ones with the same
+// external identifiers and returns their counts. If one of the buildings is
+// rejected, none of them are upserted.
+func (repository *BuildingRepositoryImpl) UpsertBatch(
+		ctx context.Context,
+		infos []*logic.ExternalBuildingInfo) (*logic.BuildingUpsertCounts, error) {
+	for i, info := range infos {
+		if err := checkNumericRange(info.Info); err != nil {
+			return nil, fmt.Errorf("failed to upsert building %d: %w", i, err)
+		}
+	}
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	var counts logic.BuildingUpsertCounts
+	for _, info := range infos {
+		if _, inserted := repository.upsert(info); inserted {
+			counts.Inserted++
+		} else {
+			counts.Updated++
+		}
+	}
+	return &counts, nil
+}
+
+// Deletes passed stored building with its external identifier. Lock must be
+// held by the caller.
+func (repository *BuildingRepositoryImpl) delete(building *domain.Building) {
+	delete(repository.buildings, building.Id)
+	if building.ExternalId != nil {
+		delete(repository.externalIds, *building.ExternalId)
+	}
+}
+
+// Inserts a copy of passed building information with optional external
+// identifier and returns stored building. Lock must be held by the caller.
+func (repository *BuildingRepositoryImpl) insert(
+		info *domain.BuildingInfo,
+		externalId *domain.ExternalId) *domain.Building {
+	repository.lastId++
+	infoCopy := *info
+	building := domain.NewBuilding(repository.lastId, &infoCopy)
+
+	if externalId != nil {
+		externalIdCopy := *externalId
+		building.ExternalId = &externalIdCopy
+		repository.externalIds[externalIdCopy] = building.Id
+	}
+
+	repository.buildings[building.Id] = building
+	return building
+}
+
+// Selects copies of the buildings according to the filter parameters, orders
+// and paginates them the same way as the database does. Lock must be held by
+// the caller.
+func (repository *BuildingRepositoryImpl) selectBuildings(
+		filters *logic.BuildingFilters) []*domain.Building {
+	// Complete sort keys, so the order is total and can be used for keyset
+	// pagination.
+	sort := logic.CompleteBuildingSort(filters.Sort)
+
+	// Search results without sort keys are ordered by relevance.
+	byRelevance := filters.Search != nil && len(filters.Sort) == 0
+	relevances := make(map[int64]float64)
+	var cursorRelevance float64
+	if byRelevance && filters.After != nil {
+		cursorRelevance = computeRelevance(
+			filters.After.Name, filters.After.City, filters.Search)
+	}
+
+	// Select buildings matching the filters and going after the cursor.
+	var buildings []*domain.Building
+	for _, building := range repository.buildings {
+		if !matchesFilters(building, filters) {
+			continue
+		}
+
+		if byRelevance {
+			relevance := computeRelevance(
+				building.Info.Name, building.Info.City, filters.Search)
+			relevances[building.Id] = relevance
+			if filters.After != nil &&
+					!(relevance < cursorRelevance ||
+						(relevance == cursorRelevance &&
+							building.Id > filters.After.Id)) {
+				continue
+			}
+		} else if filters.After != nil &&
+				compareWithCursor(building, filters.After, sort) <= 0 {
+			continue
+		}
+
+		buildings = append(buildings, building)
+	}
+
+	// Order buildings to make pagination stable.
+	if byRelevance {
+		slices.SortFunc(buildings, func(a, b *domain.Building) int {
+			if result := cmp.Compare(relevances[b.Id], relevances[a.Id]);
+					result != 0 {
+				return result
+			}
+			return cmp.Compare(a.Id, b.Id)
+		})
+	} else {
+		slices.SortFunc(buildings, func(a, b *domain.Building) int {
+			return compareBuildings(a, b, sort)
+		})
+	}
+
+	// Apply offset and limit if parameters are not nil.
+	if filters.Offset != nil {
+		offset := min(*filters.Offset, uint64(len(buildings)))
+		buildings = buildings[offset:]
+	}
+	if filters.Limit != nil && *filters.Limit < uint64(len(buildings)) {
+		buildings = buildings[:*filters.Limit]
+	}
+
+	// Copy selected buildings, so callers can not change stored ones.
+	for i, building := range buildings {
+		buildings[i] = copyBuilding(building)
+	}
+	return buildings
+}
+
+// Inserts passed building or updates the one with the same external identifier
+// and returns stored building and whether it is inserted. Lock must be held by
+// the caller.
+func (repository *BuildingRepositoryImpl) upsert(
+		info *logic.ExternalBuildingInfo) (*domain.Building, bool) {
+	id, ok := repository.externalIds[*info.ExternalId]
+	if !ok {
+		return repository.insert(info.Info, info.ExternalId), true
+	}
+
+	building := repository.buildings[id]
+	infoCopy := *info.Info
+	building.Info = &infoCopy
+	return building, false
+}
+
+// Creates a new empty in-memory buildings repository.
+func NewBuildingRepositoryImpl() *BuildingRepositoryImpl {
+	return &BuildingRepositoryImpl{
+		buildings: make(map[int64]*domain.Building),
+		externalIds: make(map[domain.ExternalId]int64),
+	}
+}
+
+// Checks that numeric values of the building fit integer columns of the
+// database, so the repository rejects the same buildings.
+func checkNumericRange(info *domain.BuildingInfo) error {
+	if info.HandoverYear > math.MaxInt32 || info.FloorsCount > math.MaxInt32 {
+		return errNumericValueOutOfRange
+	}
+	return nil
+}
+
+// Copies passed building, so changes of the copy do not affect the original.
+func copyBuilding(building *domain.Building) *domain.Building {
+	infoCopy := *building.Info
+	buildingCopy := domain.NewBuilding(building.Id, &infoCopy)
+	if building.ExternalId != nil {
+		externalIdCopy := *building.ExternalId
+		buildingCopy.ExternalId = &externalIdCopy
+	}
+	return buildingCopy
+}
+
+// Counts buildings by distinct values returned by passed function. Counts are
+// ordered by count in descending order and then by value. Returned slice is
+// never nil.
+func countValues[T logic.FilterValue](
+		buildings []*domain.Building,
+		getValue func(*domain.Building) T) []*logic.ValueCount[T] {
+	countsByValue := make(map[T]uint64)
+	for _, building := range buildings {
+		countsByValue[getValue(building)]++
+	}
+
+	counts := make([]*logic.ValueCount[T], 0, len(countsByValue))
+	for value, count := range countsByValue {
+		counts = append(counts, logic.NewValueCount(value, count))
+	}
+	slices.SortFunc(counts, func(a, b *logic.ValueCount[T]) int {
+		if result := cmp.Compare(b.Count, a.Count); result != 0 {
+			return result
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return counts
+}
+
+// Gets city of the building.
+func getCity(building *domain.Building) string {
+	return building.Info.City
+}
+
+// Gets floors count of the building.
+func getFloorsCount(building *domain.Building) uint64 {
+	return building.Info.FloorsCount
+}
+
+// Gets handover year of the building.
+func getHandoverYear(building *domain.Building) uint64 {
+	return building.Info.HandoverYear
+}
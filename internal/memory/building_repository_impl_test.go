@@ -0,0 +1,15 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+	"github.com/rylenko/leadgen-market-task/internal/logic/logictest"
+)
+
+func TestBuildingRepositoryImpl(t *testing.T) {
+	logictest.TestBuildingRepository(
+		t, func(*testing.T) logic.BuildingRepository {
+			return NewBuildingRepositoryImpl()
+		})
+}
@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+const (
+	// Minimal word similarity of the query to the name or the city, which
+	// makes building match the search. It is the default threshold of the
+	// pg_trgm extension.
+	wordSimilarityThreshold = 0.6
+
+	// Relevance of the building which lexemes match all words of the query.
+	// Database ranks text matches, while here all of them are ranked equally.
+	textMatchRelevance = 0.1
+)
+
+// Checks that building matches the search. Building matches if its lexemes
+// match all query words or if its name is similar to the query, which covers
+// typos. City is used only if it is included to the search.
+func matchesSearch(
+		building *domain.Building, search *logic.BuildingSearch) bool {
+	if matchesText(building, search) {
+		return true
+	}
+	if computeWordSimilarity(search.Query, building.Info.Name) >=
+			wordSimilarityThreshold {
+		return true
+	}
+	return search.IncludeCity &&
+		computeWordSimilarity(search.Query, building.Info.City) >=
+			wordSimilarityThreshold
+}
+
+// Computes relevance of the building with passed name and city. The more
+// lexemes match the query and the more name is similar to the query, the
+// greater relevance is.
+func computeRelevance(name, city string, search *logic.BuildingSearch) float64 {
+	building := domain.NewBuilding(0, domain.NewBuildingInfo(name, city, 0, 0))
+
+	var relevance float64
+	if matchesText(building, search) {
+		relevance += textMatchRelevance
+	}
+	relevance += computeWordSimilarity(search.Query, name)
+	if search.IncludeCity {
+		relevance += computeWordSimilarity(search.Query, city)
+	}
+	return relevance
+}
+
+// Checks that all words of the query are prefixes of the building lexemes. If
+// city is not included to the search, only name lexemes are matched. Query
+// without words matches nothing.
+func matchesText(building *domain.Building, search *logic.BuildingSearch) bool {
+	lexemes := getLexemes(building.Info.Name)
+	if search.IncludeCity {
+		lexemes = append(lexemes, getLexemes(building.Info.City)...)
+	}
+
+	words := getLexemes(search.Query)
+	if len(words) == 0 {
+		return false
+	}
+	for _, word := range words {
+		if !hasLexemeWithPrefix(lexemes, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// Checks that one of the lexemes starts with passed prefix.
+func hasLexemeWithPrefix(lexemes []string, prefix string) bool {
+	for _, lexeme := range lexemes {
+		if strings.HasPrefix(lexeme, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Computes similarity of the query to the most similar part of the text as a
+// share of query trigrams found in the text. It approximates word_similarity
+// function of the pg_trgm extension.
+func computeWordSimilarity(query, text string) float64 {
+	queryTrigrams := logic.GetTrigrams(query)
+	if len(queryTrigrams) == 0 {
+		return 0
+	}
+
+	textTrigrams := logic.GetTrigrams(text)
+	var common int
+	for trigram := range queryTrigrams {
+		if _, ok := textTrigrams[trigram]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(queryTrigrams))
+}
+
+// Gets lexemes of the text like "simple" text search configuration does: words
+// of letters and digits in lower case.
+func getLexemes(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
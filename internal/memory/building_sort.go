@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"cmp"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Compares buildings according to passed completed sort keys. Returns a
+// negative number if the first building goes before the second one.
+func compareBuildings(
+		a, b *domain.Building, sort []*logic.BuildingSortKey) int {
+	for _, key := range sort {
+		result := compareValues(
+			logic.GetBuildingFieldValue(a, key.Field),
+			logic.GetBuildingFieldValue(b, key.Field))
+		if key.Descending {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+// Compares building with the cursor according to passed completed sort keys.
+// Returns a positive number if the building goes after the cursor.
+func compareWithCursor(
+		building *domain.Building,
+		cursor *logic.BuildingCursor,
+		sort []*logic.BuildingSortKey) int {
+	info := domain.NewBuildingInfo(
+		cursor.Name, cursor.City, cursor.HandoverYear, cursor.FloorsCount)
+	return compareBuildings(building, domain.NewBuilding(cursor.Id, info), sort)
+}
+
+// Compares values of the same building field. Strings are compared byte-wise,
+// so the order may differ from the collation of the database.
+func compareValues(a, b any) int {
+	switch a := a.(type) {
+	case string:
+		return cmp.Compare(a, b.(string))
+	case uint64:
+		return cmp.Compare(a, b.(uint64))
+	case int64:
+		return cmp.Compare(a, b.(int64))
+	default:
+		return 0
+	}
+}
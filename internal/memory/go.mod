@@ -0,0 +1,8 @@
+module github.com/rylenko/leadgen-market-task/internal/memory
+
+go 1.22.5
+
+require (
+	github.com/rylenko/leadgen-market-task/internal/domain v0.0.0-20241016061444-911dacdffed6
+	github.com/rylenko/leadgen-market-task/internal/logic v0.0.0-20241016061444-911dacdffed6
+)
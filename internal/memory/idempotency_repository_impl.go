@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Stored state of the idempotency key.
+type idempotencyKey struct {
+	requestHash string
+	// Response of the request, which is nil while the request is in progress.
+	response *logic.IdempotentResponse
+	createdAt time.Time
+}
+
+// IdempotencyRepositoryImpl is an in-memory implementation of idempotency key
+// repository. It is safe for concurrent use. Keys are lost when the process
+// exits.
+type IdempotencyRepositoryImpl struct {
+	mutex sync.Mutex
+	keys map[string]*idempotencyKey
+}
+
+// Complete stores response of the request with passed key.
+func (repository *IdempotencyRepositoryImpl) Complete(
+		ctx context.Context,
+		key string,
+		response *logic.IdempotentResponse) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	if stored, ok := repository.keys[key]; ok {
+		stored.response = copyIdempotentResponse(response)
+	}
+	return nil
+}
+
+// Init does nothing, because repository is ready after creation.
+func (repository *IdempotencyRepositoryImpl) Init(ctx context.Context) error {
+	return nil
+}
+
+// Release deletes reservation of passed key if the request is not completed.
+func (repository *IdempotencyRepositoryImpl) Release(
+		ctx context.Context, key string) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	if stored, ok := repository.keys[key]; ok && stored.response == nil {
+		delete(repository.keys, key)
+	}
+	return nil
+}
+
+// Reserve inserts passed key or replaces expired or abandoned one and returns
+// nil. Otherwise, returns the existing record of the key.
+func (repository *IdempotencyRepositoryImpl) Reserve(
+		ctx context.Context,
+		key string,
+		requestHash string,
+		expiredBefore time.Time,
+		abandonedBefore time.Time) (*logic.IdempotencyRecord, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	// Return the record if key is used by another request, which is neither
+	// expired nor abandoned.
+	stored, ok := repository.keys[key]
+	if ok &&
+			!stored.createdAt.Before(expiredBefore) &&
+			!(stored.response == nil && stored.createdAt.Before(abandonedBefore)) {
+		return &logic.IdempotencyRecord{
+			RequestHash: stored.requestHash,
+			Response: copyIdempotentResponse(stored.response),
+		}, nil
+	}
+
+	repository.keys[key] = &idempotencyKey{
+		requestHash: requestHash,
+		createdAt: time.Now(),
+	}
+	return nil, nil
+}
+
+// Creates a new empty in-memory idempotency key repository.
+func NewIdempotencyRepositoryImpl() *IdempotencyRepositoryImpl {
+	return &IdempotencyRepositoryImpl{keys: make(map[string]*idempotencyKey)}
+}
+
+// Copies passed response if it is not nil, so changes of the copy do not
+// affect the original.
+func copyIdempotentResponse(
+		response *logic.IdempotentResponse) *logic.IdempotentResponse {
+	if response == nil {
+		return nil
+	}
+	return logic.NewIdempotentResponse(
+		response.Status, response.ContentType, slices.Clone(response.Body))
+}
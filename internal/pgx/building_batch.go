@@ -0,0 +1,104 @@
+package pgx
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Columns of the building table that are filled on insertion.
+var insertColumns = []string{"name", "city", "handover_year", "floors_count"}
+
+// Error of a single building of the batch, which is rejected by the database.
+type batchItemError struct {
+	// Index of the building in the batch.
+	index int
+	err error
+}
+
+func (e *batchItemError) Error() string {
+	return fmt.Sprintf("building %d is rejected: %v", e.index, e.err)
+}
+
+func (e *batchItemError) Unwrap() error {
+	return e.err
+}
+
+// Builds batch of insertion queries for each passed building. Each query is
+// surrounded by the savepoint, so a rejected building can be rolled back alone.
+func buildInsertBatch(infos []*domain.BuildingInfo) *pgx.Batch {
+	batch := &pgx.Batch{}
+	for _, info := range infos {
+		batch.Queue(batchItemSavepointStatement)
+		batch.Queue(
+			insertQuery,
+			info.Name,
+			info.City,
+			info.HandoverYear,
+			info.FloorsCount)
+		batch.Queue(releaseBatchItemSavepointStatement)
+	}
+	return batch
+}
+
+// Builds batch of upsert queries for each passed building.
+func buildUpsertBatch(infos []*logic.ExternalBuildingInfo) *pgx.Batch {
+	batch := &pgx.Batch{}
+	for _, info := range infos {
+		batch.Queue(upsertQuery, buildUpsertArgs(info)...)
+	}
+	return batch
+}
+
+// Builds arguments of the upsert query for passed building.
+func buildUpsertArgs(info *logic.ExternalBuildingInfo) []any {
+	return []any{
+		info.Info.Name,
+		info.Info.City,
+		info.Info.HandoverYear,
+		info.Info.FloorsCount,
+		info.ExternalId.Source,
+		info.ExternalId.Id,
+	}
+}
+
+// Adapter of building information source to the source of COPY protocol.
+type copyFromBuildingInfoSource struct {
+	source logic.BuildingInfoSource
+	// Error of the values, which is reported by the database only as failed
+	// copy.
+	err error
+}
+
+func (source *copyFromBuildingInfoSource) Next() bool {
+	return source.source.Next()
+}
+
+func (source *copyFromBuildingInfoSource) Values() ([]any, error) {
+	info := source.source.Info()
+
+	// COPY values are encoded by the client, so values that do not fit integer
+	// columns are rejected here with the same error as the database returns.
+	if info.HandoverYear > math.MaxInt32 || info.FloorsCount > math.MaxInt32 {
+		source.err = wrapNumericValueOutOfRangeError(
+			fmt.Errorf("building %q does not fit integer columns", info.Name))
+		return nil, source.err
+	}
+
+	return []any{
+		info.Name,
+		info.City,
+		info.HandoverYear,
+		info.FloorsCount,
+	}, nil
+}
+
+func (source *copyFromBuildingInfoSource) Err() error {
+	if source.err != nil {
+		return source.err
+	}
+	return source.source.Err()
+}
@@ -0,0 +1,97 @@
+package pgx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Builds query to count buildings by distinct values of passed field
+// according to filter parameters, ignoring the filter on the field itself.
+func buildFacetQuery(
+		filters *logic.BuildingFilters,
+		field logic.BuildingField) (query string, args []any) {
+	column := buildingColumns[field]
+	query = fmt.Sprintf("SELECT %s, COUNT(*) FROM building", column)
+
+	// Join filter conditions, except the one on the field, with query prefix.
+	conditions, args := buildFilterConditions(filters.WithoutFilter(field), args)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Group and order values, then close a query and return it.
+	query += fmt.Sprintf(
+		" GROUP BY %[1]s ORDER BY COUNT(*) DESC, %[1]s;", column)
+	return query, args
+}
+
+// Builds batch of facet queries for each passed field.
+func buildFacetsBatch(
+		filters *logic.BuildingFilters, fields []logic.BuildingField) *pgx.Batch {
+	batch := &pgx.Batch{}
+	for _, field := range fields {
+		query, args := buildFacetQuery(filters, field)
+		batch.Queue(query, args...)
+	}
+	return batch
+}
+
+// Scans results of the facets batch to the facets structure. Fields must be
+// the same as passed to build the batch.
+func scanFacets(
+		results pgx.BatchResults,
+		fields []logic.BuildingField) (*logic.BuildingFacets, error) {
+	var facets logic.BuildingFacets
+
+	for _, field := range fields {
+		// Try to get rows of the next facet query.
+		rows, err := results.Query()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get %s facet: %w", field, translateError(err))
+		}
+
+		// Try to scan facet values according to the field type.
+		switch field {
+		case logic.BuildingFieldCity:
+			facets.City, err = scanValueCounts[string](rows)
+		case logic.BuildingFieldHandoverYear:
+			facets.HandoverYear, err = scanValueCounts[uint64](rows)
+		case logic.BuildingFieldFloorsCount:
+			facets.FloorsCount, err = scanValueCounts[uint64](rows)
+		}
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to scan %s facet: %w", field, translateError(err))
+		}
+	}
+
+	return &facets, nil
+}
+
+// Scans value and count rows and closes them. Returned slice is never nil.
+func scanValueCounts[T logic.FilterValue](
+		rows pgx.Rows) ([]*logic.ValueCount[T], error) {
+	defer rows.Close()
+	counts := []*logic.ValueCount[T]{}
+
+	// Scan rows to the counts slice.
+	for rows.Next() {
+		var count logic.ValueCount[T]
+		if err := rows.Scan(&count.Value, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, &count)
+	}
+
+	// Check rows error after iterations completion.
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf(
+			"error after rows iteration: %w", translateError(err))
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,118 @@
+package pgx
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Builds query conditions according to filter parameters. Arguments of the
+// conditions are appended to passed arguments, so their placeholders continue
+// its numbering.
+func buildFilterConditions(
+		filters *logic.BuildingFilters,
+		args []any) (conditions []string, _ []any) {
+	// Add city filter conditions if parameter is not nil.
+	if filters.City != nil {
+		conditions, args = appendFilterConditions(
+			conditions, args, "city", filters.City)
+	}
+
+	// Add handover year filter conditions if parameter is not nil.
+	if filters.HandoverYear != nil {
+		conditions, args = appendFilterConditions(
+			conditions, args, "handover_year", filters.HandoverYear)
+	}
+
+	// Add floors count filter conditions if parameter is not nil.
+	if filters.FloorsCount != nil {
+		conditions, args = appendFilterConditions(
+			conditions, args, "floors_count", filters.FloorsCount)
+	}
+
+	// Add search condition if parameter is not nil.
+	if filters.Search != nil {
+		var condition string
+		condition, args = buildSearchCondition(filters.Search, args)
+		conditions = append(conditions, condition)
+	}
+
+	return conditions, args
+}
+
+// Appends conditions of the filter on passed column to the conditions and
+// their arguments to the arguments.
+func appendFilterConditions[T logic.FilterValue](
+		conditions []string,
+		args []any,
+		column string,
+		filter *logic.Filter[T]) ([]string, []any) {
+	// Appends a condition with a single argument.
+	appendCondition := func(format string, arg any) {
+		arg, cast := buildFilterArg(arg)
+		placeholder := fmt.Sprintf("$%d%s", len(args) + 1, cast)
+		condition := fmt.Sprintf(format, column, placeholder)
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+
+	// Add comparison conditions if their values are set.
+	if filter.Eq != nil {
+		appendCondition("%s = %s", *filter.Eq)
+	}
+	if filter.Ne != nil {
+		appendCondition("%s <> %s", *filter.Ne)
+	}
+	if filter.Gt != nil {
+		appendCondition("%s > %s", *filter.Gt)
+	}
+	if filter.Gte != nil {
+		appendCondition("%s >= %s", *filter.Gte)
+	}
+	if filter.Lt != nil {
+		appendCondition("%s < %s", *filter.Lt)
+	}
+	if filter.Lte != nil {
+		appendCondition("%s <= %s", *filter.Lte)
+	}
+
+	// Add set membership conditions if their values are set. Values are passed
+	// as a single array argument.
+	if filter.In != nil {
+		appendCondition("%s = ANY(%s)", filter.In)
+	}
+	if filter.NotIn != nil {
+		appendCondition("%s <> ALL(%s)", filter.NotIn)
+	}
+
+	return conditions, args
+}
+
+// Builds argument of the filter condition from passed value and returns it
+// with the cast of its placeholder.
+//
+// Unsigned values are passed as bigint, so values that do not fit integer
+// columns are still compared instead of failing to encode. Values that do not
+// fit bigint are clamped, which does not change the result, because columns
+// can not contain them anyway.
+func buildFilterArg(value any) (any, string) {
+	switch value := value.(type) {
+	case uint64:
+		return clampToInt64(value), "::bigint"
+	case []uint64:
+		values := make([]int64, 0, len(value))
+		for _, v := range value {
+			values = append(values, clampToInt64(v))
+		}
+		return values, "::bigint[]"
+	default:
+		return value, ""
+	}
+}
+
+// Converts passed unsigned value to int64, replacing values that do not fit it
+// with the maximum one.
+func clampToInt64(value uint64) int64 {
+	return int64(min(value, math.MaxInt64))
+}
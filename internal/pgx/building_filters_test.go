@@ -0,0 +1,72 @@
+package pgx
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+func TestBuildFilterConditions(t *testing.T) {
+	maxValue := uint64(math.MaxUint64)
+	year := uint64(2020)
+	city := "Moscow"
+
+	tests := []struct {
+		name string
+		filters *logic.BuildingFilters
+		conditions []string
+		args []any
+	}{
+		{
+			name: "no filters",
+			filters: &logic.BuildingFilters{},
+			conditions: nil,
+			args: []any{"prefix"},
+		},
+		{
+			name: "string comparison",
+			filters: &logic.BuildingFilters{
+				City: &logic.Filter[string]{Eq: &city, NotIn: []string{}},
+			},
+			conditions: []string{"city = $2", "city <> ALL($3)"},
+			args: []any{"prefix", "Moscow", []string{}},
+		},
+		{
+			name: "unsigned values are bigint",
+			filters: &logic.BuildingFilters{
+				HandoverYear: &logic.Filter[uint64]{Gte: &year},
+				FloorsCount: &logic.Filter[uint64]{
+					Lt: &maxValue,
+					In: []uint64{0, maxValue},
+				},
+			},
+			conditions: []string{
+				"handover_year >= $2::bigint",
+				"floors_count < $3::bigint",
+				"floors_count = ANY($4::bigint[])",
+			},
+			args: []any{
+				"prefix",
+				int64(2020),
+				int64(math.MaxInt64),
+				[]int64{0, math.MaxInt64},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conditions, args := buildFilterConditions(
+				test.filters, []any{"prefix"})
+			if !slices.Equal(conditions, test.conditions) {
+				t.Errorf("got conditions %q, want %q", conditions, test.conditions)
+			}
+			if !reflect.DeepEqual(args, test.args) {
+				t.Errorf("got arguments %#v, want %#v", args, test.args)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package pgx
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+)
+
+// Iterator over buildings, which scans rows of the open query one at a time.
+// Connection is held until the iterator is closed or exhausted.
+type buildingRowsIterator struct {
+	rows pgx.Rows
+	building *domain.Building
+	err error
+}
+
+func (iterator *buildingRowsIterator) Next() bool {
+	if iterator.err != nil || !iterator.rows.Next() {
+		return false
+	}
+
+	// Try to scan a building. Rows are closed on error to release connection.
+	building, err := scanBuilding(iterator.rows)
+	if err != nil {
+		iterator.err = fmt.Errorf(
+			"failed to scan a building: %w", translateError(err))
+		iterator.rows.Close()
+		return false
+	}
+
+	iterator.building = building
+	return true
+}
+
+func (iterator *buildingRowsIterator) Building() *domain.Building {
+	return iterator.building
+}
+
+func (iterator *buildingRowsIterator) Err() error {
+	if iterator.err != nil {
+		return iterator.err
+	}
+	if err := iterator.rows.Err(); err != nil {
+		return fmt.Errorf("error after rows iteration: %w", translateError(err))
+	}
+	return nil
+}
+
+func (iterator *buildingRowsIterator) Close() {
+	iterator.rows.Close()
+}
@@ -2,47 +2,101 @@ package pgx
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rylenko/leadgen-market-task/internal/domain"
 	"github.com/rylenko/leadgen-market-task/internal/logic"
 )
 
+// Numeric values of the buildings are passed to the statements as numeric, so
+// values that do not fit integer columns are rejected by the database with
+// numeric value out of range error instead of failing to encode on the client.
 const (
-	createCityIndexStatement = `
-		CREATE INDEX IF NOT EXISTS building_city_index
-			ON building USING HASH (city);
+	batchItemSavepointStatement = `
+		SAVEPOINT batch_item;
 	`
 
-	createFloorsCountIndexStatement = `
-		CREATE INDEX IF NOT EXISTS building_floors_count_index
-			ON building (floors_count);
+	countQueryPrefix = `
+		SELECT COUNT(*) FROM building
 	`
 
-	createHandoverYearIndexStatement = `
-		CREATE INDEX IF NOT EXISTS building_handover_year_index
-			ON building (handover_year);
+	deleteDuplicateQuery = `
+		DELETE FROM building WHERE id = $1
+			RETURNING external_source, external_id;
 	`
 
-	createTableStatement = `
-		CREATE TABLE IF NOT EXISTS building (
-			id SERIAL PRIMARY KEY,
-			name TEXT NOT NULL,
-			city TEXT NOT NULL,
-			handover_year INTEGER NOT NULL CHECK (handover_year >= 0),
-			floors_count INTEGER NOT NULL CHECK (floors_count >= 0)
-		);
+	deleteStatement = `
+		DELETE FROM building WHERE id = $1;
 	`
 
 	getAllQueryPrefix = `
-		SELECT id, name, city, handover_year, floors_count FROM building
+		SELECT id, name, city, handover_year, floors_count, external_source,
+			external_id FROM building
+	`
+
+	getQuery = `
+		SELECT id, name, city, handover_year, floors_count, external_source,
+			external_id FROM building WHERE id = $1;
 	`
 
 	insertQuery = `
 		INSERT INTO building (name, city, handover_year, floors_count)
-			VALUES ($1, $2, $3, $4) RETURNING (id);
+			VALUES ($1, $2, $3::numeric, $4::numeric) RETURNING (id);
+	`
+
+	mergeQuery = `
+		UPDATE building SET
+				external_source = CASE WHEN external_source IS NULL
+					THEN $2 ELSE external_source END,
+				external_id = CASE WHEN external_source IS NULL
+					THEN $3 ELSE external_id END
+			WHERE id = $1
+			RETURNING id, name, city, handover_year, floors_count, external_source,
+				external_id;
+	`
+
+	patchQueryPrefix = `
+		UPDATE building SET
+	`
+
+	patchQuerySuffix = `
+		RETURNING id, name, city, handover_year, floors_count, external_source,
+			external_id
+	`
+
+	releaseBatchItemSavepointStatement = `
+		RELEASE SAVEPOINT batch_item;
+	`
+
+	rollbackToBatchItemSavepointStatement = `
+		ROLLBACK TO SAVEPOINT batch_item;
+	`
+
+	upsertQuery = `
+		INSERT INTO building
+				(name, city, handover_year, floors_count, external_source, external_id)
+			VALUES ($1, $2, $3::numeric, $4::numeric, $5, $6)
+			ON CONFLICT (external_source, external_id) DO UPDATE SET
+				name = EXCLUDED.name,
+				city = EXCLUDED.city,
+				handover_year = EXCLUDED.handover_year,
+				floors_count = EXCLUDED.floors_count
+			RETURNING id, name, city, handover_year, floors_count, external_source,
+				external_id, (xmax = 0);
+	`
+
+	updateQuery = `
+		UPDATE building
+			SET name = $2, city = $3, handover_year = $4::numeric,
+				floors_count = $5::numeric
+			WHERE id = $1
+			RETURNING id, name, city, handover_year, floors_count, external_source,
+				external_id;
 	`
 )
 
@@ -56,72 +110,141 @@ func (repository *BuildingRepositoryImpl) Close() {
 	repository.pool.Close()
 }
 
-func (repository *BuildingRepositoryImpl) GetAll(
-		ctx context.Context,
-		filters *logic.BuildingFilters) ([]*domain.Building, error) {
-	var buildings []*domain.Building
-
+// Count counts all buildings according to the passed filter parameters,
+// ignoring pagination.
+func (repository *BuildingRepositoryImpl) Count(
+		ctx context.Context, filters *logic.BuildingFilters) (uint64, error) {
 	// Build query with its arguments.
-	query, args := buildGetAllQuery(filters)
+	query, args := buildCountQuery(filters)
+
+	// Execute query and try to scan buildings count.
+	row := repository.pool.QueryRow(ctx, query, args...)
+	var count uint64
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf(
+			"failed to count buildings with filter parameters %+v: %w",
+			filters,
+			translateError(err))
+	}
 
-	// Try to execute query.
-	rows, err := repository.pool.Query(ctx, query, args...)
+	return count, nil
+}
+
+// Delete deletes a building by its identifier. If building does not exist,
+// logic.ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Delete(
+		ctx context.Context, id int64) error {
+	// Try to execute deletion statement.
+	tag, err := repository.pool.Exec(ctx, deleteStatement, id)
 	if err != nil {
+		return fmt.Errorf(
+			"failed to delete building %d: %w", id, translateError(err))
+	}
+
+	// Check that the building was actually deleted.
+	if tag.RowsAffected() == 0 {
+		return logic.ErrBuildingNotFound
+	}
+
+	return nil
+}
+
+// Get gets a building by its identifier. If building does not exist,
+// logic.ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Get(
+		ctx context.Context, id int64) (*domain.Building, error) {
+	// Execute query and try to scan a building.
+	row := repository.pool.QueryRow(ctx, getQuery, id)
+	building, err := scanBuilding(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, logic.ErrBuildingNotFound
+	} else if err != nil {
 		return nil, fmt.Errorf(
-			"failed to get all buildings with filter parameters %+v: %v", filters, err)
+			"failed to get building %d: %w", id, translateError(err))
 	}
-	defer rows.Close()
 
-	// Scan rows to the buildings slice.
-	for rows.Next() {
-		// Try to scan a building.
-		var (
-			building domain.Building
-			info domain.BuildingInfo
-		)
-		err := rows.Scan(
-			&building.Id,
-			&info.Name,
-			&info.City,
-			&info.HandoverYear,
-			&info.FloorsCount)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan a building: %v", err)
-		}
-		building.Info = &info
+	return building, nil
+}
 
-		// Append scanned building to the slice.
-		buildings = append(buildings, &building)
+// GetAll gets all buildings according to the passed filter parameters.
+func (repository *BuildingRepositoryImpl) GetAll(
+		ctx context.Context,
+		filters *logic.BuildingFilters) ([]*domain.Building, error) {
+	// Try to open iterator over buildings.
+	iterator, err := repository.Iterate(ctx, filters)
+	if err != nil {
+		return nil, err
 	}
+	defer iterator.Close()
 
-	// Check rows error after iterations completion.
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error after rows iteration: %v", err)
+	// Collect buildings to the slice.
+	var buildings []*domain.Building
+	for iterator.Next() {
+		buildings = append(buildings, iterator.Building())
+	}
+	if err := iterator.Err(); err != nil {
+		return nil, err
 	}
 
 	return buildings, nil
 }
 
-// Init creates database table and indexes if they are not exists.
-func (repository *BuildingRepositoryImpl) Init(ctx context.Context) error {
-	// Try to create database table.
-	if err := repository.createTable(ctx); err != nil {
-		return fmt.Errorf("failed to create table: %v", err)
+// GetFacets counts buildings by distinct values of each passed field according
+// to the passed filter parameters, ignoring the filter on the field itself.
+// Facet queries are sent in a single batch.
+func (repository *BuildingRepositoryImpl) GetFacets(
+		ctx context.Context,
+		filters *logic.BuildingFilters,
+		fields []logic.BuildingField) (*logic.BuildingFacets, error) {
+	// Try to send batch of facet queries.
+	results := repository.pool.SendBatch(ctx, buildFacetsBatch(filters, fields))
+	defer results.Close()
+
+	// Try to scan facets.
+	facets, err := scanFacets(results, fields)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get facets with filter parameters %+v: %w",
+			filters,
+			translateError(err))
 	}
 
-	// Try to create index on city field.
-	if err := repository.createCityIndex(ctx); err != nil {
-		return fmt.Errorf("failed to create city index: %v", err)
+	return facets, nil
+}
+
+// GetStats gets aggregate statistics of the buildings according to the passed
+// filter parameters, ignoring sort keys and pagination.
+func (repository *BuildingRepositoryImpl) GetStats(
+		ctx context.Context,
+		filters *logic.BuildingFilters) (*logic.BuildingStats, error) {
+	// Build query with its arguments.
+	query, args := buildGetStatsQuery(filters)
+
+	// Try to execute query.
+	rows, err := repository.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get stats with filter parameters %+v: %w",
+			filters,
+			translateError(err))
 	}
+	defer rows.Close()
+
+	// Try to scan statistics.
+	return scanStats(rows)
+}
 
-	// Try to create index on handover year field.
-	if err := repository.createHandoverYearIndex(ctx); err != nil {
-		return fmt.Errorf("failed to create handover year index: %v", err)
+// Init applies pending migrations of the database schema.
+func (repository *BuildingRepositoryImpl) Init(ctx context.Context) error {
+	// Try to create migrator using the pool of the repository.
+	migrator, err := newMigrator(repository.pool)
+	if err != nil {
+		return fmt.Errorf("failed to create migrator: %w", err)
 	}
 
-	// Try to create index on floors count field.
-	if err := repository.createFloorsCountIndex(ctx); err != nil {
-		return fmt.Errorf("failed to create floors count index: %v", err)
+	// Try to apply pending migrations.
+	if _, err := migrator.Up(ctx); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
 	}
 
 	return nil
@@ -137,38 +260,336 @@ func (repository *BuildingRepositoryImpl) Insert(
 	// Scan returned id of a new building in the database.
 	var id int64
 	if err := row.Scan(&id); err != nil {
-		return nil, fmt.Errorf("failed to scan id of a new building: %v", err)
+		return nil, fmt.Errorf(
+			"failed to scan id of a new building: %w", translateError(err))
 	}
 
 	return domain.NewBuilding(id, info), nil
 }
 
-// Creates city index in the database.
-func (repository *BuildingRepositoryImpl) createCityIndex(
-		ctx context.Context) error {
-	_, err := repository.pool.Exec(ctx, createCityIndexStatement)
-	return err
+// InsertBatch inserts passed buildings to the database and returns result of
+// each of them in the same order.
+//
+// All buildings are inserted in a single transaction and each of them is
+// surrounded by a savepoint. If the database rejects one of them, atomic batch
+// is aborted and transaction is rolled back. Otherwise, only the rejected
+// building is rolled back to its savepoint and the rest after it are sent
+// again, so there are as many round trips as rejected buildings plus one.
+func (repository *BuildingRepositoryImpl) InsertBatch(
+		ctx context.Context,
+		infos []*domain.BuildingInfo,
+		atomic bool) ([]*logic.BuildingBatchResult, error) {
+	// Try to begin a transaction, which is rolled back if it is not committed.
+	tx, err := repository.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	results := make([]*logic.BuildingBatchResult, len(infos))
+	for start := 0; start < len(infos); {
+		// Try to insert buildings starting from the first one without result and
+		// set inserted ones.
+		ids, err := insertAll(ctx, tx, infos[start:])
+		for j, id := range ids {
+			building := domain.NewBuilding(id, infos[start + j])
+			results[start + j] = logic.NewBuildingBatchResult(building, nil)
+		}
+
+		var itemErr *batchItemError
+		if !errors.As(err, &itemErr) {
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to insert batch of %d buildings: %w", len(infos), err)
+			}
+			break
+		}
+
+		// Set error of the rejected building.
+		rejected := start + itemErr.index
+		results[rejected] = logic.NewBuildingBatchResult(
+			nil, translateError(itemErr.err))
+
+		// Abort other buildings of atomic batch, so transaction is not committed.
+		if atomic {
+			for i := range results {
+				if i != rejected {
+					results[i] = logic.NewBuildingBatchResult(
+						nil, logic.ErrBuildingBatchAborted)
+				}
+			}
+			return results, nil
+		}
+
+		// Try to roll back only the rejected building and continue after it.
+		_, err = tx.Exec(ctx, rollbackToBatchItemSavepointStatement)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to roll back rejected building: %w", translateError(err))
+		}
+		start = rejected + 1
+	}
+
+	// Try to commit inserted buildings.
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf(
+			"failed to commit transaction: %w", translateError(err))
+	}
+
+	return results, nil
+}
+
+// InsertStream inserts all buildings of passed source to the database using
+// COPY protocol and returns their count. Buildings are sent as they are read
+// from the source. If the source or the database fails, nothing is inserted.
+func (repository *BuildingRepositoryImpl) InsertStream(
+		ctx context.Context, source logic.BuildingInfoSource) (uint64, error) {
+	// Try to copy buildings of the source to the table.
+	copySource := &copyFromBuildingInfoSource{source: source}
+	count, err := repository.pool.CopyFrom(
+		ctx, pgx.Identifier{"building"}, insertColumns, copySource)
+
+	// Database reports failure of the source only as canceled copy, so the
+	// error of the source itself is returned.
+	if sourceErr := copySource.Err(); sourceErr != nil {
+		return 0, fmt.Errorf("failed to read buildings: %w", sourceErr)
+	} else if err != nil {
+		return 0, fmt.Errorf(
+			"failed to copy buildings: %w", translateError(err))
+	}
+
+	return uint64(count), nil
+}
+
+// Iterate opens an iterator over buildings according to the passed filter
+// parameters. Query stays open and rows are scanned as the iterator is
+// advanced, so the iterator must be closed to release connection.
+func (repository *BuildingRepositoryImpl) Iterate(
+		ctx context.Context,
+		filters *logic.BuildingFilters) (logic.BuildingIterator, error) {
+	// Build query with its arguments.
+	query, args := buildGetAllQuery(filters)
+
+	// Try to execute query.
+	rows, err := repository.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get all buildings with filter parameters %+v: %w",
+			filters,
+			translateError(err))
+	}
+
+	return &buildingRowsIterator{rows: rows}, nil
+}
+
+// Merge deletes a duplicate building and moves its external identifier to a
+// building with passed identifier if the latter does not have one. Both
+// changes are made in a single transaction. If one of the buildings does not
+// exist, ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Merge(
+		ctx context.Context, id, duplicateId int64) (*domain.Building, error) {
+	// Try to begin a transaction, which is rolled back if it is not committed.
+	tx, err := repository.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	// Try to delete duplicate first, so its external identifier is free.
+	var externalSource, externalId *string
+	err = tx.QueryRow(ctx, deleteDuplicateQuery, duplicateId).Scan(
+		&externalSource, &externalId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, logic.ErrBuildingNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf(
+			"failed to delete duplicate building %d: %w",
+			duplicateId,
+			translateError(err))
+	}
+
+	// Try to move external identifier to the building.
+	row := tx.QueryRow(ctx, mergeQuery, id, externalSource, externalId)
+	building, err := scanBuilding(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, logic.ErrBuildingNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf(
+			"failed to merge into building %d: %w", id, translateError(err))
+	}
+
+	// Try to commit merge.
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf(
+			"failed to commit transaction: %w", translateError(err))
+	}
+
+	return building, nil
+}
+
+// Patch changes only passed fields of a building with passed identifier. If
+// building does not exist, logic.ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Patch(
+		ctx context.Context,
+		id int64,
+		patch *logic.BuildingPatch) (*domain.Building, error) {
+	// Nothing to update, so just get current building.
+	if patch.IsEmpty() {
+		return repository.Get(ctx, id)
+	}
+
+	// Build query with its arguments.
+	query, args := buildPatchQuery(id, patch)
+
+	// Execute query and try to scan updated building.
+	row := repository.pool.QueryRow(ctx, query, args...)
+	building, err := scanBuilding(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, logic.ErrBuildingNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf(
+			"failed to patch building %d with %+v: %w",
+			id,
+			patch,
+			translateError(err))
+	}
+
+	return building, nil
 }
 
-// Creates floors count index in the database.
-func (repository *BuildingRepositoryImpl) createFloorsCountIndex(
-		ctx context.Context) error {
-	_, err := repository.pool.Exec(ctx, createFloorsCountIndexStatement)
-	return err
+// Suggest gets at most limit most frequent distinct values of passed field
+// that start with passed prefix, ignoring case.
+func (repository *BuildingRepositoryImpl) Suggest(
+		ctx context.Context,
+		field logic.BuildingField,
+		prefix string,
+		limit uint64) ([]*logic.BuildingSuggestion, error) {
+	var suggestions []*logic.BuildingSuggestion
+
+	// Build query with its arguments.
+	query, args := buildSuggestQuery(field, prefix, limit)
+
+	// Try to execute query.
+	rows, err := repository.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to suggest %s values with prefix %q: %w",
+			field,
+			prefix,
+			translateError(err))
+	}
+	defer rows.Close()
+
+	// Scan rows to the suggestions slice.
+	for rows.Next() {
+		var suggestion logic.BuildingSuggestion
+		if err := rows.Scan(&suggestion.Value, &suggestion.Count); err != nil {
+			return nil, fmt.Errorf(
+				"failed to scan a suggestion: %w", translateError(err))
+		}
+		suggestions = append(suggestions, &suggestion)
+	}
+
+	// Check rows error after iterations completion.
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf(
+			"error after rows iteration: %w", translateError(err))
+	}
+
+	return suggestions, nil
+}
+
+// Update replaces information of a building with passed identifier. If
+// building does not exist, logic.ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Update(
+		ctx context.Context,
+		id int64,
+		info *domain.BuildingInfo) (*domain.Building, error) {
+	// Execute update query and try to scan updated building.
+	row := repository.pool.QueryRow(
+		ctx,
+		updateQuery,
+		id,
+		info.Name,
+		info.City,
+		info.HandoverYear,
+		info.FloorsCount)
+	building, err := scanBuilding(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, logic.ErrBuildingNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf(
+			"failed to update building %d: %w", id, translateError(err))
+	}
+
+	return building, nil
 }
 
-// Creates handover year index in the database.
-func (repository *BuildingRepositoryImpl) createHandoverYearIndex(
-		ctx context.Context) error {
-	_, err := repository.pool.Exec(ctx, createHandoverYearIndexStatement)
-	return err
+// Upsert inserts passed building or updates the one with the same external
+// identifier. Returns the building and whether it is inserted.
+func (repository *BuildingRepositoryImpl) Upsert(
+		ctx context.Context,
+		info *logic.ExternalBuildingInfo) (*domain.Building, bool, error) {
+	// Execute upsert query and try to scan upserted building.
+	var inserted bool
+	row := repository.pool.QueryRow(ctx, upsertQuery, buildUpsertArgs(info)...)
+	building, err := scanBuilding(row, &inserted)
+	if err != nil {
+		return nil, false, fmt.Errorf(
+			"failed to upsert building %s/%s: %w",
+			info.ExternalId.Source,
+			info.ExternalId.Id,
+			translateError(err))
+	}
+
+	return building, inserted, nil
 }
 
-// Creates buildings table in the database.
-func (repository *BuildingRepositoryImpl) createTable(
-		ctx context.Context) error {
-	_, err := repository.pool.Exec(ctx, createTableStatement)
-	return err
+// UpsertBatch inserts passed buildings or updates the ones with the same
+// external identifiers and returns their counts. All buildings are upserted in
+// a single transaction and a single round trip.
+func (repository *BuildingRepositoryImpl) UpsertBatch(
+		ctx context.Context,
+		infos []*logic.ExternalBuildingInfo) (*logic.BuildingUpsertCounts, error) {
+	// Try to begin a transaction, which is rolled back if it is not committed.
+	tx, err := repository.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	// Send all upsert queries and count inserted and updated buildings.
+	var counts logic.BuildingUpsertCounts
+	results := tx.SendBatch(ctx, buildUpsertBatch(infos))
+	for i := range infos {
+		var inserted bool
+		if _, err := scanBuilding(results.QueryRow(), &inserted); err != nil {
+			results.Close()
+			return nil, fmt.Errorf(
+				"failed to upsert building %d: %w", i, translateError(err))
+		}
+
+		if inserted {
+			counts.Inserted++
+		} else {
+			counts.Updated++
+		}
+	}
+	if err := results.Close(); err != nil {
+		return nil, fmt.Errorf(
+			"failed to close batch results: %w", translateError(err))
+	}
+
+	// Try to commit upserted buildings.
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf(
+			"failed to commit transaction: %w", translateError(err))
+	}
+
+	return &counts, nil
 }
 
 // Opens a new connection to building repository.
@@ -180,36 +601,57 @@ func OpenBuildingRepositoryImpl(
 		return nil, err
 	}
 
-	// Create a new database wrapper instance.
-	impl := &BuildingRepositoryImpl{pool: pool}
-	return impl, nil
+	return NewBuildingRepositoryImpl(pool), nil
+}
+
+// Creates a new building repository using passed connection pool, which can be
+// shared with other repositories. Closing any of them closes the pool.
+func NewBuildingRepositoryImpl(pool *pgxpool.Pool) *BuildingRepositoryImpl {
+	return &BuildingRepositoryImpl{pool: pool}
+}
+
+// Builds query to count all buildings according to filter parameters,
+// ignoring pagination.
+func buildCountQuery(
+		filters *logic.BuildingFilters) (query string, args []any) {
+	query = countQueryPrefix
+
+	// Join filter conditions with query prefix.
+	conditions, args := buildFilterConditions(filters, args)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Close a query and return it.
+	query += ";"
+	return query, args
 }
 
 // Builds query to get all buildings according to filter parameters.
 func buildGetAllQuery(
 		filters *logic.BuildingFilters) (query string, args []any) {
 	query = getAllQueryPrefix
-	var conditions []string
 
-	// Add city filter if parameter is not nil.
-	if filters.City != nil {
-		condition := fmt.Sprintf("city = $%d", len(args) + 1)
-		conditions = append(conditions, condition)
-		args = append(args, *filters.City)
-	}
+	// Build filter conditions.
+	conditions, args := buildFilterConditions(filters, args)
 
-	// Add handover year filter if parameter is not nil.
-	if filters.HandoverYear != nil {
-		condition := fmt.Sprintf("handover_year = $%d", len(args) + 1)
-		conditions = append(conditions, condition)
-		args = append(args, *filters.HandoverYear)
-	}
+	// Complete sort keys, so the order is total and can be used for keyset
+	// pagination.
+	sort := logic.CompleteBuildingSort(filters.Sort)
 
-	// Add floors count filter if parameter is not nil.
-	if filters.FloorsCount != nil {
-		condition := fmt.Sprintf("floors_count = $%d", len(args) + 1)
+	// Search results without sort keys are ordered by relevance.
+	byRelevance := filters.Search != nil && len(filters.Sort) == 0
+
+	// Add keyset pagination condition if cursor is not nil.
+	if filters.After != nil {
+		var condition string
+		if byRelevance {
+			condition, args = buildRelevanceKeysetCondition(
+				filters.Search, filters.After, args)
+		} else {
+			condition, args = buildKeysetCondition(sort, filters.After, args)
+		}
 		conditions = append(conditions, condition)
-		args = append(args, *filters.FloorsCount)
 	}
 
 	// Join conditions with query prefix.
@@ -217,7 +659,155 @@ func buildGetAllQuery(
 		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
+	// Order buildings to make pagination stable.
+	if byRelevance {
+		var relevance string
+		relevance, args = buildRelevanceExpression(
+			filters.Search, "search_vector", "name", "city", args)
+		query += " ORDER BY " + relevance + " DESC, id"
+	} else {
+		query += buildOrderByClause(sort)
+	}
+
+	// Add limit if parameter is not nil.
+	if filters.Limit != nil {
+		query += fmt.Sprintf(" LIMIT $%d", len(args) + 1)
+		args = append(args, *filters.Limit)
+	}
+
+	// Add offset if parameter is not nil.
+	if filters.Offset != nil {
+		query += fmt.Sprintf(" OFFSET $%d", len(args) + 1)
+		args = append(args, *filters.Offset)
+	}
+
 	// Close a query and return it.
 	query += ";"
 	return query, args
 }
+
+// Builds query to change only passed fields of a building with passed
+// identifier. Patch must not be empty.
+func buildPatchQuery(
+		id int64, patch *logic.BuildingPatch) (query string, args []any) {
+	query = patchQueryPrefix
+	var assignments []string
+
+	// Add name assignment if parameter is not nil.
+	if patch.Name != nil {
+		assignment := fmt.Sprintf("name = $%d", len(args) + 1)
+		assignments = append(assignments, assignment)
+		args = append(args, *patch.Name)
+	}
+
+	// Add city assignment if parameter is not nil.
+	if patch.City != nil {
+		assignment := fmt.Sprintf("city = $%d", len(args) + 1)
+		assignments = append(assignments, assignment)
+		args = append(args, *patch.City)
+	}
+
+	// Add handover year assignment if parameter is not nil.
+	if patch.HandoverYear != nil {
+		assignment := fmt.Sprintf(
+			"handover_year = $%d::numeric", len(args) + 1)
+		assignments = append(assignments, assignment)
+		args = append(args, *patch.HandoverYear)
+	}
+
+	// Add floors count assignment if parameter is not nil.
+	if patch.FloorsCount != nil {
+		assignment := fmt.Sprintf(
+			"floors_count = $%d::numeric", len(args) + 1)
+		assignments = append(assignments, assignment)
+		args = append(args, *patch.FloorsCount)
+	}
+
+	// Join assignments with query prefix and add identifier condition.
+	query += strings.Join(assignments, ", ")
+	query += fmt.Sprintf(" WHERE id = $%d", len(args) + 1)
+	args = append(args, id)
+
+	// Close a query and return it.
+	query += patchQuerySuffix + ";"
+	return query, args
+}
+
+// Inserts all passed buildings in passed transaction and a single round trip
+// and returns their identifiers. If the database rejects one of the buildings,
+// identifiers of the buildings before it are returned with *batchItemError and
+// transaction stays inside the savepoint of the rejected building.
+func insertAll(
+		ctx context.Context,
+		tx pgx.Tx,
+		infos []*domain.BuildingInfo) ([]int64, error) {
+	// Send all insertion queries with their savepoints.
+	results := tx.SendBatch(ctx, buildInsertBatch(infos))
+	defer results.Close()
+
+	// Scan identifiers of new buildings.
+	ids := make([]int64, 0, len(infos))
+	for i := range infos {
+		// Try to create savepoint of the building.
+		if _, err := results.Exec(); err != nil {
+			return nil, fmt.Errorf(
+				"failed to create savepoint: %w", translateError(err))
+		}
+
+		// Try to scan id of the building. Database errors are caused by the
+		// building itself.
+		var id int64
+		if err := results.QueryRow().Scan(&id); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				return ids, &batchItemError{index: i, err: err}
+			}
+			return nil, fmt.Errorf(
+				"failed to scan id of a new building: %w", translateError(err))
+		}
+
+		// Try to release savepoint of the inserted building.
+		if _, err := results.Exec(); err != nil {
+			return nil, fmt.Errorf(
+				"failed to release savepoint: %w", translateError(err))
+		}
+		ids = append(ids, id)
+	}
+	if err := results.Close(); err != nil {
+		return nil, fmt.Errorf(
+			"failed to close batch results: %w", translateError(err))
+	}
+
+	return ids, nil
+}
+
+// Scans building columns of the row and then passed destinations of other
+// columns.
+func scanBuilding(row pgx.Row, dest ...any) (*domain.Building, error) {
+	var (
+		building domain.Building
+		info domain.BuildingInfo
+		externalSource *string
+		externalId *string
+	)
+
+	// Try to scan building columns.
+	err := row.Scan(append([]any{
+		&building.Id,
+		&info.Name,
+		&info.City,
+		&info.HandoverYear,
+		&info.FloorsCount,
+		&externalSource,
+		&externalId,
+	}, dest...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	building.Info = &info
+	if externalSource != nil && externalId != nil {
+		building.ExternalId = domain.NewExternalId(*externalSource, *externalId)
+	}
+	return &building, nil
+}
@@ -0,0 +1,45 @@
+package pgx
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+	"github.com/rylenko/leadgen-market-task/internal/logic/logictest"
+)
+
+// Environment variable with URI of the database for tests. Tests are skipped
+// if it is not set. All data of the database is removed by the tests.
+const testURIVariable = "PG_TEST_URI"
+
+const truncateStatement = `
+	TRUNCATE building RESTART IDENTITY;
+`
+
+func TestBuildingRepositoryImpl(t *testing.T) {
+	uri := os.Getenv(testURIVariable)
+	if uri == "" {
+		t.Skipf("%s is not set", testURIVariable)
+	}
+
+	// Open repository and apply migrations once for all tests.
+	ctx := context.Background()
+	repository, err := OpenBuildingRepositoryImpl(ctx, uri)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	defer repository.Close()
+	if err := repository.Init(ctx); err != nil {
+		t.Fatalf("failed to initialize repository: %v", err)
+	}
+
+	// Each test starts with empty table.
+	logictest.TestBuildingRepository(
+		t, func(t *testing.T) logic.BuildingRepository {
+			if _, err := repository.pool.Exec(ctx, truncateStatement); err != nil {
+				t.Fatalf("failed to truncate buildings: %v", err)
+			}
+			return repository
+		})
+}
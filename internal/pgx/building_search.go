@@ -0,0 +1,134 @@
+package pgx
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Format of search vector expression, which accepts name and city
+// expressions. Name lexemes have weight A and city lexemes have weight B.
+//
+// Text search uses "simple" configuration, because names are proper nouns in
+// different languages, so they must not be stemmed.
+//
+// Generated search vector column of the migrations uses the same expression.
+const searchVectorFormat = `
+	setweight(to_tsvector('simple', %s), 'A') ||
+		setweight(to_tsvector('simple', %s), 'B')
+`
+
+// Builds condition that selects buildings matching the search. Building
+// matches if its search vector matches all query words or if its name is
+// similar to the query, which covers typos. Arguments of the condition are
+// appended to passed arguments.
+func buildSearchCondition(
+		search *logic.BuildingSearch, args []any) (string, []any) {
+	textQueryPlaceholder := fmt.Sprintf("$%d", len(args) + 1)
+	queryPlaceholder := fmt.Sprintf("$%d", len(args) + 2)
+	args = append(args, buildTextSearchQuery(search), search.Query)
+
+	alternatives := []string{
+		fmt.Sprintf(
+			"search_vector @@ to_tsquery('simple', %s)", textQueryPlaceholder),
+		fmt.Sprintf("%s <%% name", queryPlaceholder),
+	}
+	if search.IncludeCity {
+		alternatives = append(
+			alternatives, fmt.Sprintf("%s <%% city", queryPlaceholder))
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// Builds relevance expression of the building with passed search vector, name
+// and city expressions. The more search vector matches the query and the more
+// name is similar to the query, the greater relevance is. Arguments of the
+// expression are appended to passed arguments.
+func buildRelevanceExpression(
+		search *logic.BuildingSearch,
+		vector, name, city string,
+		args []any) (string, []any) {
+	textQueryPlaceholder := fmt.Sprintf("$%d", len(args) + 1)
+	queryPlaceholder := fmt.Sprintf("$%d", len(args) + 2)
+	args = append(args, buildTextSearchQuery(search), search.Query)
+
+	expression := fmt.Sprintf(
+		"ts_rank(%s, to_tsquery('simple', %s)) + word_similarity(%s, %s)",
+		vector,
+		textQueryPlaceholder,
+		queryPlaceholder,
+		name)
+	if search.IncludeCity {
+		expression += fmt.Sprintf(
+			" + word_similarity(%s, %s)", queryPlaceholder, city)
+	}
+
+	return "(" + expression + ")", args
+}
+
+// Builds keyset pagination condition that selects buildings going after the
+// cursor in relevance order. Relevance of the cursor is computed from its
+// name and city, so it is equal to the relevance of the building itself.
+func buildRelevanceKeysetCondition(
+		search *logic.BuildingSearch,
+		cursor *logic.BuildingCursor,
+		args []any) (string, []any) {
+	// Build relevance expression of the rows.
+	rowRelevance, args := buildRelevanceExpression(
+		search, "search_vector", "name", "city", args)
+
+	// Build relevance expression of the cursor.
+	name := fmt.Sprintf("$%d::text", len(args) + 1)
+	city := fmt.Sprintf("$%d::text", len(args) + 2)
+	args = append(args, cursor.Name, cursor.City)
+	vector := fmt.Sprintf(searchVectorFormat, name, city)
+	cursorRelevance, args := buildRelevanceExpression(
+		search, vector, name, city, args)
+
+	// Less relevant buildings or equally relevant buildings with greater
+	// identifiers go after the cursor.
+	idPlaceholder := fmt.Sprintf("$%d", len(args) + 1)
+	args = append(args, cursor.Id)
+	condition := fmt.Sprintf(
+		"(%s < %s OR (%s = %s AND id > %s))",
+		rowRelevance,
+		cursorRelevance,
+		rowRelevance,
+		cursorRelevance,
+		idPlaceholder)
+
+	return condition, args
+}
+
+// Builds text search query which matches building if all words of the search
+// query are prefixes of its lexemes. Words are stripped of everything except
+// letters and digits, so the query is always valid. If city is not included
+// to the search, only name lexemes are matched.
+func buildTextSearchQuery(search *logic.BuildingSearch) string {
+	// Only name lexemes have weight A.
+	weights := "A"
+	if search.IncludeCity {
+		weights = "AB"
+	}
+
+	var terms []string
+	for _, word := range strings.Fields(search.Query) {
+		// Strip special characters of the word.
+		word = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, word)
+
+		// Match word as a prefix of lexemes with passed weights.
+		if word != "" {
+			terms = append(terms, word + ":*" + weights)
+		}
+	}
+
+	return strings.Join(terms, " & ")
+}
@@ -0,0 +1,72 @@
+package pgx
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Columns of the building table by building fields. Only these columns may be
+// inserted into queries, so fields never reach SQL directly.
+var buildingColumns = map[logic.BuildingField]string{
+	logic.BuildingFieldId: "id",
+	logic.BuildingFieldName: "name",
+	logic.BuildingFieldCity: "city",
+	logic.BuildingFieldHandoverYear: "handover_year",
+	logic.BuildingFieldFloorsCount: "floors_count",
+}
+
+// Builds ORDER BY clause according to passed completed sort keys.
+func buildOrderByClause(sort []*logic.BuildingSortKey) string {
+	terms := make([]string, 0, len(sort))
+	for _, key := range sort {
+		term := buildingColumns[key.Field]
+		if key.Descending {
+			term += " DESC"
+		}
+		terms = append(terms, term)
+	}
+	return " ORDER BY " + strings.Join(terms, ", ")
+}
+
+// Builds keyset pagination condition that selects buildings going after the
+// cursor according to passed completed sort keys. Arguments of the condition
+// are appended to passed arguments.
+//
+// For keys (a, b DESC, id) the condition is:
+//
+//	a > $1 OR (a = $1 AND b < $2) OR (a = $1 AND b = $2 AND id > $3)
+func buildKeysetCondition(
+		sort []*logic.BuildingSortKey,
+		cursor *logic.BuildingCursor,
+		args []any) (string, []any) {
+	var (
+		alternatives []string
+		equalities []string
+	)
+
+	for _, key := range sort {
+		column := buildingColumns[key.Field]
+		placeholder := fmt.Sprintf("$%d", len(args) + 1)
+		args = append(args, cursor.Value(key.Field))
+
+		// Buildings that have equal previous keys and go after the cursor by
+		// the current key.
+		operator := ">"
+		if key.Descending {
+			operator = "<"
+		}
+		comparison := fmt.Sprintf("%s %s %s", column, operator, placeholder)
+		alternative := append(slices.Clone(equalities), comparison)
+		alternatives = append(
+			alternatives, "(" + strings.Join(alternative, " AND ") + ")")
+
+		// Next keys are compared only if the current ones are equal.
+		equalities = append(
+			equalities, fmt.Sprintf("%s = %s", column, placeholder))
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
@@ -0,0 +1,105 @@
+package pgx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+const (
+	// Statistics are computed by a single query with grouping sets, so all of
+	// them are consistent. GROUPING() tells which set a row belongs to.
+	getStatsQueryPrefix = `
+		SELECT
+			GROUPING(city, handover_year),
+			city,
+			handover_year,
+			COUNT(*),
+			MIN(floors_count),
+			MAX(floors_count),
+			AVG(floors_count)::DOUBLE PRECISION
+		FROM building
+	`
+
+	getStatsQuerySuffix = `
+		GROUP BY GROUPING SETS ((city), (handover_year), ())
+		ORDER BY GROUPING(city, handover_year), COUNT(*) DESC, city, handover_year
+	`
+
+	// Values of GROUPING(city, handover_year) for each grouping set.
+	cityGrouping = 1
+	handoverYearGrouping = 2
+	totalGrouping = 3
+)
+
+// Builds query to get aggregate statistics of the buildings according to
+// filter parameters, ignoring sort keys and pagination.
+func buildGetStatsQuery(
+		filters *logic.BuildingFilters) (query string, args []any) {
+	query = getStatsQueryPrefix
+
+	// Join filter conditions with query prefix.
+	conditions, args := buildFilterConditions(filters, args)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Close a query and return it.
+	query += getStatsQuerySuffix + ";"
+	return query, args
+}
+
+// Scans rows of the statistics query to the statistics structure.
+func scanStats(rows pgx.Rows) (*logic.BuildingStats, error) {
+	var stats logic.BuildingStats
+
+	for rows.Next() {
+		// Try to scan a row of one of the grouping sets.
+		var (
+			grouping int
+			city *string
+			handoverYear *uint64
+			count uint64
+			minFloorsCount *uint64
+			maxFloorsCount *uint64
+			avgFloorsCount *float64
+		)
+		err := rows.Scan(
+			&grouping,
+			&city,
+			&handoverYear,
+			&count,
+			&minFloorsCount,
+			&maxFloorsCount,
+			&avgFloorsCount)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to scan stats row: %w", translateError(err))
+		}
+
+		// Place row values according to its grouping set.
+		switch grouping {
+		case cityGrouping:
+			stats.CityCounts = append(
+				stats.CityCounts, logic.NewValueCount(*city, count))
+		case handoverYearGrouping:
+			stats.HandoverYearCounts = append(
+				stats.HandoverYearCounts, logic.NewValueCount(*handoverYear, count))
+		case totalGrouping:
+			stats.Count = count
+			stats.MinFloorsCount = minFloorsCount
+			stats.MaxFloorsCount = maxFloorsCount
+			stats.AvgFloorsCount = avgFloorsCount
+		}
+	}
+
+	// Check rows error after iterations completion.
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf(
+			"error after rows iteration: %w", translateError(err))
+	}
+
+	return &stats, nil
+}
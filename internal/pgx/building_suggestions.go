@@ -0,0 +1,37 @@
+package pgx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Format of the query to get distinct values of a column by prefix, which
+// accepts column name. Prefix matching uses lowercase values, so it is served
+// by text_pattern_ops indexes on them.
+const suggestQueryFormat = `
+	SELECT %[1]s, COUNT(*) FROM building
+		WHERE lower(%[1]s) LIKE lower($1) || '%%'
+		GROUP BY %[1]s
+		ORDER BY COUNT(*) DESC, %[1]s
+		LIMIT $2;
+`
+
+// Builds query to get at most limit most frequent distinct values of passed
+// field that start with passed prefix.
+func buildSuggestQuery(
+		field logic.BuildingField,
+		prefix string,
+		limit uint64) (query string, args []any) {
+	query = fmt.Sprintf(suggestQueryFormat, buildingColumns[field])
+	args = append(args, escapeLikePattern(prefix), limit)
+	return query, args
+}
+
+// Escapes special characters of LIKE pattern, so passed string is matched
+// literally.
+func escapeLikePattern(str string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(str)
+}
@@ -0,0 +1,91 @@
+package pgx
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Codes of the PostgreSQL errors, which are translated to the logic errors.
+const (
+	uniqueViolationCode = "23505"
+	checkViolationCode = "23514"
+	numericValueOutOfRangeCode = "22003"
+)
+
+// Classes and codes of the PostgreSQL errors, which mean that database is
+// temporarily unavailable.
+var unavailableCodePrefixes = []string{
+	// Connection exception.
+	"08",
+	// Insufficient resources.
+	"53",
+	// Operator intervention: admin shutdown, crash shutdown, can not connect
+	// now.
+	"57P01",
+	"57P02",
+	"57P03",
+}
+
+// Translates passed database error to the logic error of the corresponding
+// kind. Returns passed error as is if it has no corresponding kind.
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case uniqueViolationCode:
+			return logic.WrapError(
+				logic.ErrConflict,
+				"unique_violation",
+				"building conflicts with an existing one",
+				err)
+		case checkViolationCode:
+			return logic.WrapError(
+				logic.ErrValidation,
+				"check_violation",
+				"building violates a constraint of the storage",
+				err)
+		case numericValueOutOfRangeCode:
+			return wrapNumericValueOutOfRangeError(err)
+		}
+		for _, prefix := range unavailableCodePrefixes {
+			if strings.HasPrefix(pgErr.Code, prefix) {
+				return wrapUnavailableError(err)
+			}
+		}
+		return err
+	}
+
+	// Errors of the connection, which happened before query is processed.
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.As(err, &connectErr) ||
+			errors.As(err, &netErr) ||
+			pgconn.Timeout(err) ||
+			pgconn.SafeToRetry(err) {
+		return wrapUnavailableError(err)
+	}
+	return err
+}
+
+// Wraps passed error into the error of ErrValidation kind, which means that
+// numeric value of the building does not fit its column.
+func wrapNumericValueOutOfRangeError(err error) error {
+	return logic.WrapError(
+		logic.ErrValidation,
+		"numeric_value_out_of_range",
+		"numeric value of the building is out of range",
+		err)
+}
+
+// Wraps passed error into the error of ErrUnavailable kind.
+func wrapUnavailableError(err error) error {
+	return logic.WrapError(
+		logic.ErrUnavailable,
+		"database_unavailable",
+		"database is unavailable",
+		err)
+}
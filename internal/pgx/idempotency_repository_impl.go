@@ -0,0 +1,185 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+const (
+	completeIdempotencyKeyStatement = `
+		UPDATE idempotency_key
+			SET status = $2, content_type = $3, body = $4
+			WHERE key = $1;
+	`
+
+	getIdempotencyKeyQuery = `
+		SELECT request_hash, status, content_type, body FROM idempotency_key
+			WHERE key = $1;
+	`
+
+	releaseIdempotencyKeyStatement = `
+		DELETE FROM idempotency_key WHERE key = $1 AND status IS NULL;
+	`
+
+	reserveIdempotencyKeyQuery = `
+		INSERT INTO idempotency_key (key, request_hash) VALUES ($1, $2)
+			ON CONFLICT (key) DO UPDATE SET
+				request_hash = EXCLUDED.request_hash,
+				status = NULL,
+				content_type = NULL,
+				body = NULL,
+				created_at = now()
+			WHERE idempotency_key.created_at < $3
+				OR (idempotency_key.status IS NULL
+					AND idempotency_key.created_at < $4)
+			RETURNING key;
+	`
+)
+
+// IdempotencyRepositoryImpl is a pgx implementation of idempotency key
+// repository.
+type IdempotencyRepositoryImpl struct {
+	pool *pgxpool.Pool
+}
+
+// Closes opened repository implementation.
+func (repository *IdempotencyRepositoryImpl) Close() {
+	repository.pool.Close()
+}
+
+// Complete stores response of the request with passed key.
+func (repository *IdempotencyRepositoryImpl) Complete(
+		ctx context.Context,
+		key string,
+		response *logic.IdempotentResponse) error {
+	_, err := repository.pool.Exec(
+		ctx,
+		completeIdempotencyKeyStatement,
+		key,
+		response.Status,
+		response.ContentType,
+		response.Body)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to complete idempotency key %q: %w",
+			key,
+			translateError(err))
+	}
+	return nil
+}
+
+// Init applies pending migrations of the database schema.
+func (repository *IdempotencyRepositoryImpl) Init(ctx context.Context) error {
+	migrator, err := newMigrator(repository.pool)
+	if err != nil {
+		return fmt.Errorf("failed to create migrator: %w", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return nil
+}
+
+// Release deletes reservation of passed key if the request is not completed.
+func (repository *IdempotencyRepositoryImpl) Release(
+		ctx context.Context, key string) error {
+	_, err := repository.pool.Exec(ctx, releaseIdempotencyKeyStatement, key)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to release idempotency key %q: %w",
+			key,
+			translateError(err))
+	}
+	return nil
+}
+
+// Reserve inserts passed key or replaces expired or abandoned one and returns
+// nil. Otherwise, returns the existing record of the key. If the existing
+// reservation is released before its record is got, reserving is retried.
+func (repository *IdempotencyRepositoryImpl) Reserve(
+		ctx context.Context,
+		key string,
+		requestHash string,
+		expiredBefore time.Time,
+		abandonedBefore time.Time) (*logic.IdempotencyRecord, error) {
+	for {
+		// Try to reserve the key.
+		var reserved string
+		err := repository.pool.QueryRow(
+			ctx,
+			reserveIdempotencyKeyQuery,
+			key,
+			requestHash,
+			expiredBefore,
+			abandonedBefore).Scan(&reserved)
+		if err == nil {
+			return nil, nil
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf(
+				"failed to reserve idempotency key %q: %w",
+				key,
+				translateError(err))
+		}
+
+		// Key is used by another request, so try to get its record.
+		record, err := repository.get(ctx, key)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get idempotency key %q: %w", key, translateError(err))
+		}
+		return record, nil
+	}
+}
+
+// Gets the record of passed key.
+func (repository *IdempotencyRepositoryImpl) get(
+		ctx context.Context, key string) (*logic.IdempotencyRecord, error) {
+	var (
+		record logic.IdempotencyRecord
+		status *int
+		contentType *string
+		body []byte
+	)
+	err := repository.pool.QueryRow(ctx, getIdempotencyKeyQuery, key).Scan(
+		&record.RequestHash, &status, &contentType, &body)
+	if err != nil {
+		return nil, err
+	}
+	if status != nil {
+		var contentTypeValue string
+		if contentType != nil {
+			contentTypeValue = *contentType
+		}
+		record.Response = logic.NewIdempotentResponse(
+			*status, contentTypeValue, body)
+	}
+
+	return &record, nil
+}
+
+// Opens a new connection to idempotency key repository.
+func OpenIdempotencyRepositoryImpl(
+		ctx context.Context, uri string) (*IdempotencyRepositoryImpl, error) {
+	// Try to open a new database connection pool.
+	pool, err := pgxpool.New(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIdempotencyRepositoryImpl(pool), nil
+}
+
+// Creates a new idempotency key repository using passed connection pool, which
+// can be shared with other repositories. Closing any of them closes the pool.
+func NewIdempotencyRepositoryImpl(
+		pool *pgxpool.Pool) *IdempotencyRepositoryImpl {
+	return &IdempotencyRepositoryImpl{pool: pool}
+}
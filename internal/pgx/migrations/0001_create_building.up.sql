@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS building (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	city TEXT NOT NULL,
+	handover_year INTEGER NOT NULL CHECK (handover_year >= 0),
+	floors_count INTEGER NOT NULL CHECK (floors_count >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS building_city_index
+	ON building USING HASH (city);
+
+CREATE INDEX IF NOT EXISTS building_handover_year_index
+	ON building (handover_year);
+
+CREATE INDEX IF NOT EXISTS building_floors_count_index
+	ON building (floors_count);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS building_search_vector_index;
+
+ALTER TABLE building DROP COLUMN IF EXISTS search_vector;
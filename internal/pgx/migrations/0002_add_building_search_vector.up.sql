@@ -0,0 +1,8 @@
+ALTER TABLE building ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (
+		setweight(to_tsvector('simple', name), 'A') ||
+			setweight(to_tsvector('simple', city), 'B')
+	) STORED;
+
+CREATE INDEX IF NOT EXISTS building_search_vector_index
+	ON building USING GIN (search_vector);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS building_city_trigram_index;
+
+DROP INDEX IF EXISTS building_name_trigram_index;
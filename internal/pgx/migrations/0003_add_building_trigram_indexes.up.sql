@@ -0,0 +1,7 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS building_name_trigram_index
+	ON building USING GIN (name gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS building_city_trigram_index
+	ON building USING GIN (city gin_trgm_ops);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS building_city_prefix_index;
+
+DROP INDEX IF EXISTS building_name_prefix_index;
@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS building_name_prefix_index
+	ON building (lower(name) text_pattern_ops);
+
+CREATE INDEX IF NOT EXISTS building_city_prefix_index
+	ON building (lower(city) text_pattern_ops);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS building_external_id_index;
+
+ALTER TABLE building
+	DROP COLUMN IF EXISTS external_id,
+	DROP COLUMN IF EXISTS external_source;
@@ -0,0 +1,6 @@
+ALTER TABLE building
+	ADD COLUMN IF NOT EXISTS external_source TEXT,
+	ADD COLUMN IF NOT EXISTS external_id TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS building_external_id_index
+	ON building (external_source, external_id);
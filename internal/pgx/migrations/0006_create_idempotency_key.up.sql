@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS idempotency_key (
+	key TEXT PRIMARY KEY,
+	request_hash TEXT NOT NULL,
+	status INTEGER,
+	content_type TEXT,
+	body BYTEA,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
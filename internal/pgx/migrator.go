@@ -0,0 +1,440 @@
+package pgx
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// Key of the advisory lock, which is held while migrations are applied or
+	// reverted, so several replicas can start at once.
+	migrationLockKey int64 = 4_818_276_013_944_051
+
+	createMigrationTableStatement = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+	`
+
+	deleteMigrationStatement = `
+		DELETE FROM schema_migrations WHERE version = $1;
+	`
+
+	getAppliedMigrationsQuery = `
+		SELECT version, applied_at FROM schema_migrations ORDER BY version;
+	`
+
+	insertMigrationStatement = `
+		INSERT INTO schema_migrations (version, name) VALUES ($1, $2);
+	`
+
+	lockMigrationsStatement = `
+		SELECT pg_advisory_lock($1);
+	`
+
+	unlockMigrationsStatement = `
+		SELECT pg_advisory_unlock($1);
+	`
+)
+
+// Files of the migrations, which are named as <version>_<name>.up.sql and
+// <version>_<name>.down.sql.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Pattern of the migration file name with version, name and direction.
+var migrationFileNamePattern = regexp.MustCompile(
+	`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned change of the database schema with statements to
+// apply and revert it.
+type Migration struct {
+	Version uint64
+	Name string
+	Up string
+	Down string
+}
+
+// MigrationStatus is a migration with the time it was applied at. Time is nil
+// if the migration is pending.
+type MigrationStatus struct {
+	Migration *Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies and reverts embedded migrations of the database schema.
+// Applied migrations are stored in schema_migrations table.
+type Migrator struct {
+	pool *pgxpool.Pool
+	// Migrations sorted by their versions.
+	migrations []*Migration
+}
+
+// Closes opened migrator.
+func (migrator *Migrator) Close() {
+	migrator.pool.Close()
+}
+
+// Down reverts the last applied migration and returns it. Nil is returned if
+// there are no applied migrations.
+func (migrator *Migrator) Down(ctx context.Context) (*Migration, error) {
+	var reverted *Migration
+	err := migrator.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := getAppliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			return nil
+		}
+
+		// Find the last applied migration.
+		versions := make([]uint64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		last := slices.Max(versions)
+		migration := migrator.find(last)
+		if migration == nil {
+			return fmt.Errorf("applied migration %d is unknown", last)
+		}
+
+		// Try to revert it.
+		if err := revertMigration(ctx, conn, migration); err != nil {
+			return err
+		}
+		reverted = migration
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reverted, nil
+}
+
+// Status gets all known migrations with the time they were applied at.
+func (migrator *Migrator) Status(
+		ctx context.Context) ([]*MigrationStatus, error) {
+	// Try to acquire a connection to read applied migrations.
+	conn, err := migrator.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	// Try to get applied migrations.
+	if _, err := conn.Exec(ctx, createMigrationTableStatement); err != nil {
+		return nil, fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	applied, err := getAppliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Match known migrations with applied ones.
+	statuses := make([]*MigrationStatus, 0, len(migrator.migrations))
+	for _, migration := range migrator.migrations {
+		status := &MigrationStatus{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// To applies or reverts migrations, so the schema matches passed version, and
+// returns them in the order they were applied or reverted. Zero version
+// reverts all migrations.
+func (migrator *Migrator) To(
+		ctx context.Context, version uint64) ([]*Migration, error) {
+	if version != 0 && migrator.find(version) == nil {
+		return nil, fmt.Errorf("migration %d is unknown", version)
+	}
+
+	var changed []*Migration
+	err := migrator.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := getAppliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		// Revert applied migrations after the version from the last one.
+		for i := len(migrator.migrations) - 1; i >= 0; i-- {
+			migration := migrator.migrations[i]
+			if migration.Version <= version {
+				break
+			}
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := revertMigration(ctx, conn, migration); err != nil {
+				return err
+			}
+			changed = append(changed, migration)
+		}
+
+		// Apply pending migrations up to the version from the first one.
+		for _, migration := range migrator.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := applyMigration(ctx, conn, migration); err != nil {
+				return err
+			}
+			changed = append(changed, migration)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changed, nil
+}
+
+// Up applies all pending migrations and returns them in the order they were
+// applied.
+func (migrator *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	if len(migrator.migrations) == 0 {
+		return nil, nil
+	}
+	last := migrator.migrations[len(migrator.migrations) - 1]
+	return migrator.To(ctx, last.Version)
+}
+
+// Finds known migration with passed version. Returns nil if there is no such
+// migration.
+func (migrator *Migrator) find(version uint64) *Migration {
+	i, ok := slices.BinarySearchFunc(
+		migrator.migrations,
+		version,
+		func(migration *Migration, version uint64) int {
+			return compareVersions(migration.Version, version)
+		})
+	if !ok {
+		return nil
+	}
+	return migrator.migrations[i]
+}
+
+// Calls passed function with a connection, which holds migrations lock. Table
+// of the applied migrations is created before the call.
+func (migrator *Migrator) withLock(
+		ctx context.Context, fn func(conn *pgxpool.Conn) error) (err error) {
+	// Try to acquire a connection, because advisory lock belongs to the
+	// session.
+	conn, err := migrator.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	// Try to take the lock, waiting for other replicas.
+	_, err = conn.Exec(ctx, lockMigrationsStatement, migrationLockKey)
+	if err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer func() {
+		// Connection is closed if the lock can not be released, so the session
+		// and its lock end.
+		_, unlockErr := conn.Exec(
+			context.Background(), unlockMigrationsStatement, migrationLockKey)
+		if unlockErr != nil {
+			conn.Conn().Close(context.Background())
+			if err == nil {
+				err = fmt.Errorf("failed to unlock migrations: %w", unlockErr)
+			}
+		}
+	}()
+
+	// Try to create table of the applied migrations.
+	if _, err := conn.Exec(ctx, createMigrationTableStatement); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// Opens a new migrator using passed database URI.
+func OpenMigrator(ctx context.Context, uri string) (*Migrator, error) {
+	// Try to open a new database connection pool.
+	pool, err := pgxpool.New(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	// Try to create migrator using the pool.
+	migrator, err := newMigrator(pool)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return migrator, nil
+}
+
+// Applies passed migration and stores it as applied in a single transaction.
+func applyMigration(
+		ctx context.Context, conn *pgxpool.Conn, migration *Migration) error {
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, migration.Up); err != nil {
+			return err
+		}
+		_, err := tx.Exec(
+			ctx, insertMigrationStatement, migration.Version, migration.Name)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"failed to apply migration %d %s: %w",
+			migration.Version,
+			migration.Name,
+			err)
+	}
+	return nil
+}
+
+// Compares passed versions of migrations.
+func compareVersions(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Gets times of the applied migrations by their versions.
+func getAppliedMigrations(
+		ctx context.Context, conn *pgxpool.Conn) (map[uint64]time.Time, error) {
+	rows, err := conn.Query(ctx, getAppliedMigrationsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[uint64]time.Time)
+	for rows.Next() {
+		var (
+			version int64
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[uint64(version)] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after rows iteration: %w", err)
+	}
+
+	return applied, nil
+}
+
+// Loads embedded migrations sorted by their versions. Every migration must
+// have both up and down files.
+func loadMigrations() ([]*Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	migrationsByVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		// Parse version, name and direction of the migration.
+		matches := migrationFileNamePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version %s", matches[1])
+		}
+
+		// Try to read statements of the migration.
+		content, err := fs.ReadFile(migrationFiles, "migrations/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		// Add statements to the migration of the version.
+		migration, ok := migrationsByVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			migrationsByVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d has several names", version)
+		}
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	// Check and sort migrations.
+	migrations := make([]*Migration, 0, len(migrationsByVersion))
+	for _, migration := range migrationsByVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf(
+				"migration %d must have up and down files", migration.Version)
+		}
+		migrations = append(migrations, migration)
+	}
+	slices.SortFunc(migrations, func(a, b *Migration) int {
+		return compareVersions(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+// Creates a new migrator using passed pool.
+func newMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// Reverts passed migration and removes it from applied ones in a single
+// transaction.
+func revertMigration(
+		ctx context.Context, conn *pgxpool.Conn, migration *Migration) error {
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, migration.Down); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, deleteMigrationStatement, migration.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"failed to revert migration %d %s: %w",
+			migration.Version,
+			migration.Name,
+			err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Builds query to count buildings by distinct values of passed field
+// according to filter parameters, ignoring the filter on the field itself.
+func buildFacetQuery(
+		filters *logic.BuildingFilters,
+		field logic.BuildingField) (query string, args []any) {
+	column := buildingColumns[field]
+	query = fmt.Sprintf("SELECT %s, COUNT(*) FROM building", column)
+
+	// Join filter conditions, except the one on the field, with query prefix.
+	conditions, args := buildFilterConditions(filters.WithoutFilter(field), args)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Group and order values, then close a query and return it.
+	query += fmt.Sprintf(
+		" GROUP BY %[1]s ORDER BY COUNT(*) DESC, %[1]s;", column)
+	return query, args
+}
+
+// Scans value and count rows and closes them. Returned slice is never nil.
+func scanValueCounts[T logic.FilterValue](
+		rows *sql.Rows) ([]*logic.ValueCount[T], error) {
+	defer rows.Close()
+	counts := []*logic.ValueCount[T]{}
+
+	// Scan rows to the counts slice.
+	for rows.Next() {
+		var count logic.ValueCount[T]
+		if err := rows.Scan(&count.Value, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, &count)
+	}
+
+	// Check rows error after iterations completion.
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf(
+			"error after rows iteration: %w", translateError(err))
+	}
+
+	return counts, nil
+}
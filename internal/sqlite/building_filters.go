@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Builds query conditions according to filter parameters. Arguments of the
+// conditions are appended to passed arguments, so their placeholders continue
+// its numbering.
+func buildFilterConditions(
+		filters *logic.BuildingFilters,
+		args []any) (conditions []string, _ []any) {
+	// Add city filter conditions if parameter is not nil.
+	if filters.City != nil {
+		conditions, args = appendFilterConditions(
+			conditions, args, "city", filters.City)
+	}
+
+	// Add handover year filter conditions if parameter is not nil.
+	if filters.HandoverYear != nil {
+		conditions, args = appendFilterConditions(
+			conditions, args, "handover_year", filters.HandoverYear)
+	}
+
+	// Add floors count filter conditions if parameter is not nil.
+	if filters.FloorsCount != nil {
+		conditions, args = appendFilterConditions(
+			conditions, args, "floors_count", filters.FloorsCount)
+	}
+
+	// Add search condition if parameter is not nil.
+	if filters.Search != nil {
+		var condition string
+		condition, args = buildSearchCondition(filters.Search, args)
+		conditions = append(conditions, condition)
+	}
+
+	return conditions, args
+}
+
+// Appends conditions of the filter on passed column to the conditions and
+// their arguments to the arguments.
+func appendFilterConditions[T logic.FilterValue](
+		conditions []string,
+		args []any,
+		column string,
+		filter *logic.Filter[T]) ([]string, []any) {
+	// Appends a placeholder of passed value to the arguments and returns it.
+	appendArg := func(value T) string {
+		args = append(args, buildArg(value))
+		return fmt.Sprintf("?%d", len(args))
+	}
+
+	// Appends a condition with a single argument.
+	appendCondition := func(format string, value T) {
+		condition := fmt.Sprintf(format, column, appendArg(value))
+		conditions = append(conditions, condition)
+	}
+
+	// Appends a condition with a list of arguments. SQLite accepts empty
+	// lists, so empty In matches nothing and empty NotIn matches everything.
+	appendListCondition := func(format string, values []T) {
+		placeholders := make([]string, 0, len(values))
+		for _, value := range values {
+			placeholders = append(placeholders, appendArg(value))
+		}
+		condition := fmt.Sprintf(
+			format, column, strings.Join(placeholders, ", "))
+		conditions = append(conditions, condition)
+	}
+
+	// Add comparison conditions if their values are set.
+	if filter.Eq != nil {
+		appendCondition("%s = %s", *filter.Eq)
+	}
+	if filter.Ne != nil {
+		appendCondition("%s <> %s", *filter.Ne)
+	}
+	if filter.Gt != nil {
+		appendCondition("%s > %s", *filter.Gt)
+	}
+	if filter.Gte != nil {
+		appendCondition("%s >= %s", *filter.Gte)
+	}
+	if filter.Lt != nil {
+		appendCondition("%s < %s", *filter.Lt)
+	}
+	if filter.Lte != nil {
+		appendCondition("%s <= %s", *filter.Lte)
+	}
+
+	// Add set membership conditions if their values are set.
+	if filter.In != nil {
+		appendListCondition("%s IN (%s)", filter.In)
+	}
+	if filter.NotIn != nil {
+		appendListCondition("%s NOT IN (%s)", filter.NotIn)
+	}
+
+	return conditions, args
+}
+
+// Builds argument of the statement from passed value.
+//
+// Driver does not accept unsigned values that do not fit int64, so they are
+// clamped, which does not change the result of comparison, because columns
+// can not contain them anyway.
+func buildArg(value any) any {
+	if value, ok := value.(uint64); ok {
+		return int64(min(value, math.MaxInt64))
+	}
+	return value
+}
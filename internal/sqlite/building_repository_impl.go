@@ -0,0 +1,881 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/rylenko/leadgen-market-task/internal/domain"
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Name and city are followed by lowercase values, lexemes and trigrams
+// derived from them, which are passed by the repository on each write.
+const (
+	countQueryPrefix = `
+		SELECT COUNT(*) FROM building
+	`
+
+	deleteDuplicateQuery = `
+		DELETE FROM building WHERE id = ?1
+			RETURNING external_source, external_id;
+	`
+
+	deleteStatement = `
+		DELETE FROM building WHERE id = ?1;
+	`
+
+	getAllQueryPrefix = `
+		SELECT id, name, city, handover_year, floors_count, external_source,
+			external_id FROM building
+	`
+
+	getIdByExternalIdQuery = `
+		SELECT id FROM building WHERE external_source = ?1 AND external_id = ?2;
+	`
+
+	getQuery = `
+		SELECT id, name, city, handover_year, floors_count, external_source,
+			external_id FROM building WHERE id = ?1;
+	`
+
+	insertQuery = `
+		INSERT INTO building
+				(name, city, handover_year, floors_count, lower_name, lower_city,
+					name_lexemes, city_lexemes, name_trigrams, city_trigrams,
+					external_source, external_id)
+			VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12)
+			RETURNING id;
+	`
+
+	mergeQuery = `
+		UPDATE building SET
+				external_source = CASE WHEN external_source IS NULL
+					THEN ?2 ELSE external_source END,
+				external_id = CASE WHEN external_source IS NULL
+					THEN ?3 ELSE external_id END
+			WHERE id = ?1
+			RETURNING id, name, city, handover_year, floors_count, external_source,
+				external_id;
+	`
+
+	patchQueryPrefix = `
+		UPDATE building SET
+	`
+
+	patchQuerySuffix = `
+		RETURNING id, name, city, handover_year, floors_count, external_source,
+			external_id
+	`
+
+	updateQuery = `
+		UPDATE building
+			SET name = ?1, city = ?2, handover_year = ?3, floors_count = ?4,
+				lower_name = ?5, lower_city = ?6, name_lexemes = ?7,
+				city_lexemes = ?8, name_trigrams = ?9, city_trigrams = ?10
+			WHERE id = ?11
+			RETURNING id, name, city, handover_year, floors_count, external_source,
+				external_id;
+	`
+)
+
+// Executor of the queries, which is either the database or its transaction.
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Row of the query, which is either a single row or the current one of rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// BuildingRepositoryImpl is a SQLite implementation of buildings repository.
+// Buildings are stored in a single database file, so it does not need a
+// database server.
+type BuildingRepositoryImpl struct {
+	db *sql.DB
+}
+
+// Closes opened repository implementation.
+func (repository *BuildingRepositoryImpl) Close() {
+	repository.db.Close()
+}
+
+// Count counts all buildings according to the passed filter parameters,
+// ignoring pagination.
+func (repository *BuildingRepositoryImpl) Count(
+		ctx context.Context, filters *logic.BuildingFilters) (uint64, error) {
+	// Build query with its arguments.
+	query, args := buildCountQuery(filters)
+
+	// Execute query and try to scan buildings count.
+	row := repository.db.QueryRowContext(ctx, query, args...)
+	var count uint64
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf(
+			"failed to count buildings with filter parameters %+v: %w",
+			filters,
+			translateError(err))
+	}
+
+	return count, nil
+}
+
+// Delete deletes a building by its identifier. If building does not exist,
+// logic.ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Delete(
+		ctx context.Context, id int64) error {
+	// Try to execute deletion statement.
+	result, err := repository.db.ExecContext(ctx, deleteStatement, id)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to delete building %d: %w", id, translateError(err))
+	}
+
+	// Check that the building was actually deleted.
+	if affected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf(
+			"failed to get deleted rows count: %w", translateError(err))
+	} else if affected == 0 {
+		return logic.ErrBuildingNotFound
+	}
+
+	return nil
+}
+
+// Get gets a building by its identifier. If building does not exist,
+// logic.ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Get(
+		ctx context.Context, id int64) (*domain.Building, error) {
+	// Execute query and try to scan a building.
+	row := repository.db.QueryRowContext(ctx, getQuery, id)
+	building, err := scanBuilding(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, logic.ErrBuildingNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get building %d: %w", id, translateError(err))
+	}
+
+	return building, nil
+}
+
+// GetAll gets all buildings according to the passed filter parameters.
+func (repository *BuildingRepositoryImpl) GetAll(
+		ctx context.Context,
+		filters *logic.BuildingFilters) ([]*domain.Building, error) {
+	// Try to open iterator over buildings.
+	iterator, err := repository.Iterate(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	// Collect buildings to the slice.
+	var buildings []*domain.Building
+	for iterator.Next() {
+		buildings = append(buildings, iterator.Building())
+	}
+	if err := iterator.Err(); err != nil {
+		return nil, err
+	}
+
+	return buildings, nil
+}
+
+// GetFacets counts buildings by distinct values of each passed field according
+// to the passed filter parameters, ignoring the filter on the field itself.
+// Facet queries are executed one by one.
+func (repository *BuildingRepositoryImpl) GetFacets(
+		ctx context.Context,
+		filters *logic.BuildingFilters,
+		fields []logic.BuildingField) (*logic.BuildingFacets, error) {
+	var facets logic.BuildingFacets
+
+	for _, field := range fields {
+		// Try to execute facet query of the field.
+		query, args := buildFacetQuery(filters, field)
+		rows, err := repository.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get %s facet with filter parameters %+v: %w",
+				field,
+				filters,
+				translateError(err))
+		}
+
+		// Try to scan facet values according to the field type.
+		switch field {
+		case logic.BuildingFieldCity:
+			facets.City, err = scanValueCounts[string](rows)
+		case logic.BuildingFieldHandoverYear:
+			facets.HandoverYear, err = scanValueCounts[uint64](rows)
+		case logic.BuildingFieldFloorsCount:
+			facets.FloorsCount, err = scanValueCounts[uint64](rows)
+		default:
+			rows.Close()
+		}
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to scan %s facet: %w", field, translateError(err))
+		}
+	}
+
+	return &facets, nil
+}
+
+// GetStats gets aggregate statistics of the buildings according to the passed
+// filter parameters, ignoring sort keys and pagination.
+func (repository *BuildingRepositoryImpl) GetStats(
+		ctx context.Context,
+		filters *logic.BuildingFilters) (*logic.BuildingStats, error) {
+	// Build query with its arguments.
+	query, args := buildGetStatsQuery(filters)
+
+	// Try to execute query.
+	rows, err := repository.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get stats with filter parameters %+v: %w",
+			filters,
+			translateError(err))
+	}
+	defer rows.Close()
+
+	// Try to scan statistics.
+	return scanStats(rows)
+}
+
+// Init creates the database schema if it does not exist.
+func (repository *BuildingRepositoryImpl) Init(ctx context.Context) error {
+	_, err := repository.db.ExecContext(ctx, createBuildingSchemaStatement)
+	if err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+	return nil
+}
+
+// Insert inserts a new building to the database.
+func (repository *BuildingRepositoryImpl) Insert(
+		ctx context.Context, info *domain.BuildingInfo) (*domain.Building, error) {
+	id, err := insert(ctx, repository.db, info, nil)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewBuilding(id, info), nil
+}
+
+// InsertBatch inserts passed buildings to the database and returns result of
+// each of them in the same order.
+//
+// All buildings are inserted in a single transaction. SQLite undoes only the
+// rejected statement, so the rejected building is excluded and the rest are
+// inserted. If the batch is atomic, transaction is rolled back and the batch is
+// aborted instead.
+func (repository *BuildingRepositoryImpl) InsertBatch(
+		ctx context.Context,
+		infos []*domain.BuildingInfo,
+		atomic bool) ([]*logic.BuildingBatchResult, error) {
+	results := make([]*logic.BuildingBatchResult, len(infos))
+
+	// Try to begin a transaction, which is rolled back if it is not committed.
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback()
+
+	for i, info := range infos {
+		// Try to insert a building.
+		id, err := insert(ctx, tx, info, nil)
+		if err != nil && !isRejection(err) {
+			return nil, fmt.Errorf(
+				"failed to insert batch of %d buildings: %w", len(infos), err)
+		} else if err == nil {
+			results[i] = logic.NewBuildingBatchResult(
+				domain.NewBuilding(id, info), nil)
+			continue
+		}
+
+		// Set error of the rejected building.
+		results[i] = logic.NewBuildingBatchResult(nil, err)
+
+		// Abort other buildings of atomic batch, including inserted ones,
+		// which are rolled back.
+		if atomic {
+			for j := range results {
+				if j != i {
+					results[j] = logic.NewBuildingBatchResult(
+						nil, logic.ErrBuildingBatchAborted)
+				}
+			}
+			return results, nil
+		}
+	}
+
+	// Try to commit inserted buildings.
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf(
+			"failed to commit transaction: %w", translateError(err))
+	}
+
+	return results, nil
+}
+
+// InsertStream inserts all buildings of passed source to the database in a
+// single transaction and returns their count. Buildings are inserted as they
+// are read from the source. If the source or the database fails, nothing is
+// inserted.
+func (repository *BuildingRepositoryImpl) InsertStream(
+		ctx context.Context, source logic.BuildingInfoSource) (uint64, error) {
+	// Try to begin a transaction, which is rolled back if it is not committed.
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback()
+
+	// Insert buildings one by one.
+	var count uint64
+	for source.Next() {
+		if _, err := insert(ctx, tx, source.Info(), nil); err != nil {
+			return 0, fmt.Errorf("failed to insert building %d: %w", count, err)
+		}
+		count++
+	}
+	if err := source.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read buildings: %w", err)
+	}
+
+	// Try to commit inserted buildings.
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf(
+			"failed to commit transaction: %w", translateError(err))
+	}
+
+	return count, nil
+}
+
+// Iterate opens an iterator over buildings according to the passed filter
+// parameters. Query stays open and rows are scanned as the iterator is
+// advanced, so the iterator must be closed to release connection.
+func (repository *BuildingRepositoryImpl) Iterate(
+		ctx context.Context,
+		filters *logic.BuildingFilters) (logic.BuildingIterator, error) {
+	// Build query with its arguments.
+	query, args := buildGetAllQuery(filters)
+
+	// Try to execute query.
+	rows, err := repository.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get all buildings with filter parameters %+v: %w",
+			filters,
+			translateError(err))
+	}
+
+	return &buildingRowsIterator{rows: rows}, nil
+}
+
+// Merge deletes a duplicate building and moves its external identifier to a
+// building with passed identifier if the latter does not have one. Both
+// changes are made in a single transaction. If one of the buildings does not
+// exist, ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Merge(
+		ctx context.Context, id, duplicateId int64) (*domain.Building, error) {
+	// Try to begin a transaction, which is rolled back if it is not committed.
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback()
+
+	// Try to delete duplicate first, so its external identifier is free.
+	var externalSource, externalId *string
+	err = tx.QueryRowContext(ctx, deleteDuplicateQuery, duplicateId).Scan(
+		&externalSource, &externalId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, logic.ErrBuildingNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf(
+			"failed to delete duplicate building %d: %w",
+			duplicateId,
+			translateError(err))
+	}
+
+	// Try to move external identifier to the building.
+	row := tx.QueryRowContext(ctx, mergeQuery, id, externalSource, externalId)
+	building, err := scanBuilding(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, logic.ErrBuildingNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf(
+			"failed to merge into building %d: %w", id, translateError(err))
+	}
+
+	// Try to commit merge.
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf(
+			"failed to commit transaction: %w", translateError(err))
+	}
+
+	return building, nil
+}
+
+// Patch changes only passed fields of a building with passed identifier. If
+// building does not exist, logic.ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Patch(
+		ctx context.Context,
+		id int64,
+		patch *logic.BuildingPatch) (*domain.Building, error) {
+	// Nothing to update, so just get current building.
+	if patch.IsEmpty() {
+		return repository.Get(ctx, id)
+	}
+
+	// Reject numeric values which can not be passed to the driver.
+	for _, value := range []*uint64{patch.HandoverYear, patch.FloorsCount} {
+		if value != nil && *value > math.MaxInt32 {
+			return nil, errNumericValueOutOfRange
+		}
+	}
+
+	// Build query with its arguments.
+	query, args := buildPatchQuery(id, patch)
+
+	// Execute query and try to scan updated building.
+	row := repository.db.QueryRowContext(ctx, query, args...)
+	building, err := scanBuilding(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, logic.ErrBuildingNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf(
+			"failed to patch building %d with %+v: %w",
+			id,
+			patch,
+			translateError(err))
+	}
+
+	return building, nil
+}
+
+// Suggest gets at most limit most frequent distinct values of passed field
+// that start with passed prefix, ignoring case.
+func (repository *BuildingRepositoryImpl) Suggest(
+		ctx context.Context,
+		field logic.BuildingField,
+		prefix string,
+		limit uint64) ([]*logic.BuildingSuggestion, error) {
+	var suggestions []*logic.BuildingSuggestion
+
+	// Build query with its arguments.
+	query, args := buildSuggestQuery(field, prefix, limit)
+
+	// Try to execute query.
+	rows, err := repository.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to suggest %s values with prefix %q: %w",
+			field,
+			prefix,
+			translateError(err))
+	}
+	defer rows.Close()
+
+	// Scan rows to the suggestions slice.
+	for rows.Next() {
+		var suggestion logic.BuildingSuggestion
+		if err := rows.Scan(&suggestion.Value, &suggestion.Count); err != nil {
+			return nil, fmt.Errorf(
+				"failed to scan a suggestion: %w", translateError(err))
+		}
+		suggestions = append(suggestions, &suggestion)
+	}
+
+	// Check rows error after iterations completion.
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf(
+			"error after rows iteration: %w", translateError(err))
+	}
+
+	return suggestions, nil
+}
+
+// Update replaces information of a building with passed identifier. If
+// building does not exist, logic.ErrBuildingNotFound is returned.
+func (repository *BuildingRepositoryImpl) Update(
+		ctx context.Context,
+		id int64,
+		info *domain.BuildingInfo) (*domain.Building, error) {
+	building, err := update(ctx, repository.db, id, info)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, logic.ErrBuildingNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return building, nil
+}
+
+// Upsert inserts passed building or updates the one with the same external
+// identifier. Returns the building and whether it is inserted.
+func (repository *BuildingRepositoryImpl) Upsert(
+		ctx context.Context,
+		info *logic.ExternalBuildingInfo) (*domain.Building, bool, error) {
+	// Try to begin a transaction, which is rolled back if it is not committed.
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf(
+			"failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback()
+
+	// Try to upsert the building.
+	building, inserted, err := upsert(ctx, tx, info)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Try to commit upserted building.
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf(
+			"failed to commit transaction: %w", translateError(err))
+	}
+
+	return building, inserted, nil
+}
+
+// UpsertBatch inserts passed buildings or updates the ones with the same
+// external identifiers and returns their counts. All buildings are upserted in
+// a single transaction.
+func (repository *BuildingRepositoryImpl) UpsertBatch(
+		ctx context.Context,
+		infos []*logic.ExternalBuildingInfo) (*logic.BuildingUpsertCounts, error) {
+	// Try to begin a transaction, which is rolled back if it is not committed.
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback()
+
+	// Upsert buildings one by one and count inserted and updated ones.
+	var counts logic.BuildingUpsertCounts
+	for i, info := range infos {
+		_, inserted, err := upsert(ctx, tx, info)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upsert building %d: %w", i, err)
+		}
+
+		if inserted {
+			counts.Inserted++
+		} else {
+			counts.Updated++
+		}
+	}
+
+	// Try to commit upserted buildings.
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf(
+			"failed to commit transaction: %w", translateError(err))
+	}
+
+	return &counts, nil
+}
+
+// Opens a new connection to building repository stored in the database file
+// with passed path.
+func OpenBuildingRepositoryImpl(
+		ctx context.Context, path string) (*BuildingRepositoryImpl, error) {
+	// Try to open the database file.
+	db, err := openDatabase(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new database wrapper instance.
+	impl := &BuildingRepositoryImpl{db: db}
+	return impl, nil
+}
+
+// Builds arguments of the insertion and update statements for passed
+// building: its information followed by the values derived from it.
+func buildInfoArgs(info *domain.BuildingInfo) []any {
+	return []any{
+		info.Name,
+		info.City,
+		info.HandoverYear,
+		info.FloorsCount,
+		strings.ToLower(info.Name),
+		strings.ToLower(info.City),
+		joinLexemes(info.Name),
+		joinLexemes(info.City),
+		joinTrigrams(info.Name),
+		joinTrigrams(info.City),
+	}
+}
+
+// Builds query to count all buildings according to filter parameters,
+// ignoring pagination.
+func buildCountQuery(
+		filters *logic.BuildingFilters) (query string, args []any) {
+	query = countQueryPrefix
+
+	// Join filter conditions with query prefix.
+	conditions, args := buildFilterConditions(filters, args)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Close a query and return it.
+	query += ";"
+	return query, args
+}
+
+// Builds query to get all buildings according to filter parameters.
+func buildGetAllQuery(
+		filters *logic.BuildingFilters) (query string, args []any) {
+	query = getAllQueryPrefix
+
+	// Build filter conditions.
+	conditions, args := buildFilterConditions(filters, args)
+
+	// Complete sort keys, so the order is total and can be used for keyset
+	// pagination.
+	sort := logic.CompleteBuildingSort(filters.Sort)
+
+	// Search results without sort keys are ordered by relevance.
+	byRelevance := filters.Search != nil && len(filters.Sort) == 0
+
+	// Add keyset pagination condition if cursor is not nil.
+	if filters.After != nil {
+		var condition string
+		if byRelevance {
+			condition, args = buildRelevanceKeysetCondition(
+				filters.Search, filters.After, args)
+		} else {
+			condition, args = buildKeysetCondition(sort, filters.After, args)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	// Join conditions with query prefix.
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Order buildings to make pagination stable.
+	if byRelevance {
+		var relevance string
+		relevance, args = buildRelevanceExpression(filters.Search, args)
+		query += " ORDER BY " + relevance + " DESC, id"
+	} else {
+		query += buildOrderByClause(sort)
+	}
+
+	// Add limit if parameter is not nil. SQLite does not accept offset without
+	// limit, so negative limit, which means no limit, is used then.
+	if filters.Limit != nil {
+		query += fmt.Sprintf(" LIMIT ?%d", len(args) + 1)
+		args = append(args, buildArg(*filters.Limit))
+	} else if filters.Offset != nil {
+		query += " LIMIT -1"
+	}
+
+	// Add offset if parameter is not nil.
+	if filters.Offset != nil {
+		query += fmt.Sprintf(" OFFSET ?%d", len(args) + 1)
+		args = append(args, buildArg(*filters.Offset))
+	}
+
+	// Close a query and return it.
+	query += ";"
+	return query, args
+}
+
+// Builds query to change only passed fields of a building with passed
+// identifier. Patch must not be empty.
+func buildPatchQuery(
+		id int64, patch *logic.BuildingPatch) (query string, args []any) {
+	query = patchQueryPrefix
+	var assignments []string
+
+	// Appends assignment of passed column to the assignments.
+	appendAssignment := func(column string, value any) {
+		assignment := fmt.Sprintf("%s = ?%d", column, len(args) + 1)
+		assignments = append(assignments, assignment)
+		args = append(args, value)
+	}
+
+	// Add name assignments if parameter is not nil.
+	if patch.Name != nil {
+		appendAssignment("name", *patch.Name)
+		appendAssignment("lower_name", strings.ToLower(*patch.Name))
+		appendAssignment("name_lexemes", joinLexemes(*patch.Name))
+		appendAssignment("name_trigrams", joinTrigrams(*patch.Name))
+	}
+
+	// Add city assignments if parameter is not nil.
+	if patch.City != nil {
+		appendAssignment("city", *patch.City)
+		appendAssignment("lower_city", strings.ToLower(*patch.City))
+		appendAssignment("city_lexemes", joinLexemes(*patch.City))
+		appendAssignment("city_trigrams", joinTrigrams(*patch.City))
+	}
+
+	// Add handover year assignment if parameter is not nil.
+	if patch.HandoverYear != nil {
+		appendAssignment("handover_year", *patch.HandoverYear)
+	}
+
+	// Add floors count assignment if parameter is not nil.
+	if patch.FloorsCount != nil {
+		appendAssignment("floors_count", *patch.FloorsCount)
+	}
+
+	// Join assignments with query prefix and add identifier condition.
+	query += strings.Join(assignments, ", ")
+	query += fmt.Sprintf(" WHERE id = ?%d", len(args) + 1)
+	args = append(args, id)
+
+	// Close a query and return it.
+	query += patchQuerySuffix + ";"
+	return query, args
+}
+
+// Checks that numeric values of the building fit integer columns. Other
+// values can not be passed to the driver, so they are rejected before the
+// statements are executed.
+func checkNumericRange(info *domain.BuildingInfo) error {
+	if info.HandoverYear > math.MaxInt32 || info.FloorsCount > math.MaxInt32 {
+		return errNumericValueOutOfRange
+	}
+	return nil
+}
+
+// Inserts passed building with passed external identifier, which may be nil,
+// and returns identifier of a new building.
+func insert(
+		ctx context.Context,
+		db querier,
+		info *domain.BuildingInfo,
+		externalId *domain.ExternalId) (int64, error) {
+	if err := checkNumericRange(info); err != nil {
+		return 0, err
+	}
+
+	// Add external identifier to the arguments if it is not nil.
+	args := append(buildInfoArgs(info), nil, nil)
+	if externalId != nil {
+		args[len(args) - 2] = externalId.Source
+		args[len(args) - 1] = externalId.Id
+	}
+
+	// Execute insertion query and scan returned id of a new building.
+	var id int64
+	err := db.QueryRowContext(ctx, insertQuery, args...).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"failed to scan id of a new building: %w", translateError(err))
+	}
+
+	return id, nil
+}
+
+// Scans building columns of the row.
+func scanBuilding(row scanner) (*domain.Building, error) {
+	var (
+		building domain.Building
+		info domain.BuildingInfo
+		externalSource *string
+		externalId *string
+	)
+
+	// Try to scan building columns.
+	err := row.Scan(
+		&building.Id,
+		&info.Name,
+		&info.City,
+		&info.HandoverYear,
+		&info.FloorsCount,
+		&externalSource,
+		&externalId)
+	if err != nil {
+		return nil, err
+	}
+
+	building.Info = &info
+	if externalSource != nil && externalId != nil {
+		building.ExternalId = domain.NewExternalId(*externalSource, *externalId)
+	}
+	return &building, nil
+}
+
+// Replaces information of a building with passed identifier. If building does
+// not exist, sql.ErrNoRows is returned.
+func update(
+		ctx context.Context,
+		db querier,
+		id int64,
+		info *domain.BuildingInfo) (*domain.Building, error) {
+	if err := checkNumericRange(info); err != nil {
+		return nil, err
+	}
+
+	// Execute update query and try to scan updated building.
+	row := db.QueryRowContext(
+		ctx, updateQuery, append(buildInfoArgs(info), id)...)
+	building, err := scanBuilding(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf(
+			"failed to update building %d: %w", id, translateError(err))
+	}
+
+	return building, nil
+}
+
+// Inserts passed building or updates the one with the same external
+// identifier using passed transaction. Returns the building and whether it is
+// inserted.
+func upsert(
+		ctx context.Context,
+		tx *sql.Tx,
+		info *logic.ExternalBuildingInfo) (*domain.Building, bool, error) {
+	// Try to find the building with the same external identifier.
+	var id int64
+	err := tx.QueryRowContext(
+		ctx,
+		getIdByExternalIdQuery,
+		info.ExternalId.Source,
+		info.ExternalId.Id).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Try to insert a new building.
+		id, err := insert(ctx, tx, info.Info, info.ExternalId)
+		if err != nil {
+			return nil, false, err
+		}
+		building := domain.NewBuilding(id, info.Info)
+		building.ExternalId = info.ExternalId
+		return building, true, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf(
+			"failed to get building %s/%s: %w",
+			info.ExternalId.Source,
+			info.ExternalId.Id,
+			translateError(err))
+	}
+
+	// Try to update the found building.
+	building, err := update(ctx, tx, id, info.Info)
+	if err != nil {
+		return nil, false, err
+	}
+	return building, false, nil
+}
@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+	"github.com/rylenko/leadgen-market-task/internal/logic/logictest"
+)
+
+func TestBuildingRepositoryImpl(t *testing.T) {
+	// Each test starts with a new database file.
+	logictest.TestBuildingRepository(
+		t, func(t *testing.T) logic.BuildingRepository {
+			ctx := context.Background()
+			path := filepath.Join(t.TempDir(), "buildings.db")
+			repository, err := OpenBuildingRepositoryImpl(ctx, path)
+			if err != nil {
+				t.Fatalf("failed to open repository: %v", err)
+			}
+			t.Cleanup(repository.Close)
+			if err := repository.Init(ctx); err != nil {
+				t.Fatalf("failed to initialize repository: %v", err)
+			}
+			return repository
+		})
+}
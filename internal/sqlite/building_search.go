@@ -0,0 +1,254 @@
+package sqlite
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Minimal word similarity of the query to the name or the city, which makes
+// building match the search, as a fraction. It is the default threshold of
+// the pg_trgm extension. Similarity is compared as integers, so the fraction
+// is kept as numerator and denominator.
+const (
+	similarityThresholdNumerator = 6
+	similarityThresholdDenominator = 10
+)
+
+// Expressions of the search over the stored lexemes and trigrams.
+type searchExpressions struct {
+	// Expression which is 1 if lexemes match all query words and 0 otherwise.
+	textMatch string
+	// Expressions counting query trigrams found in the name and the city.
+	nameSimilarity string
+	citySimilarity string
+	// Count of the query trigrams.
+	trigramsCount int
+}
+
+// Builds expressions of the search. Arguments of the expressions are appended
+// to passed arguments.
+func buildSearchExpressions(
+		search *logic.BuildingSearch,
+		args []any) (*searchExpressions, []any) {
+	expressions := &searchExpressions{textMatch: "0"}
+
+	// Each query word must be a prefix of a name lexeme or a city lexeme if
+	// city is included to the search. Lexemes are stored with a leading space,
+	// so the word with a leading space is found at the start of a lexeme.
+	var matches []string
+	for _, word := range getLexemes(search.Query) {
+		placeholder := fmt.Sprintf("?%d", len(args) + 1)
+		args = append(args, " " + word)
+
+		match := fmt.Sprintf("instr(name_lexemes, %s) > 0", placeholder)
+		if search.IncludeCity {
+			match = fmt.Sprintf(
+				"(%s OR instr(city_lexemes, %s) > 0)", match, placeholder)
+		}
+		matches = append(matches, match)
+	}
+	if len(matches) > 0 {
+		expressions.textMatch = "(" + strings.Join(matches, " AND ") + ")"
+	}
+
+	// Count query trigrams found in the stored ones. Trigrams are stored
+	// between separators, so the whole trigram is found.
+	var nameCounts, cityCounts []string
+	trigrams := getTrigrams(search.Query)
+	for _, trigram := range trigrams {
+		placeholder := fmt.Sprintf("?%d", len(args) + 1)
+		args = append(args, "|" + trigram + "|")
+
+		nameCounts = append(
+			nameCounts, fmt.Sprintf("(instr(name_trigrams, %s) > 0)", placeholder))
+		cityCounts = append(
+			cityCounts, fmt.Sprintf("(instr(city_trigrams, %s) > 0)", placeholder))
+	}
+	expressions.nameSimilarity = buildSumExpression(nameCounts)
+	expressions.citySimilarity = buildSumExpression(cityCounts)
+	expressions.trigramsCount = len(trigrams)
+
+	return expressions, args
+}
+
+// Builds condition that selects buildings matching the search. Building
+// matches if its lexemes match all query words or if its name is similar to
+// the query, which covers typos. Arguments of the condition are appended to
+// passed arguments.
+func buildSearchCondition(
+		search *logic.BuildingSearch, args []any) (string, []any) {
+	expressions, args := buildSearchExpressions(search, args)
+
+	alternatives := []string{expressions.textMatch}
+	if expressions.trigramsCount > 0 {
+		alternatives = append(
+			alternatives,
+			buildSimilarCondition(
+				expressions.nameSimilarity, expressions.trigramsCount))
+		if search.IncludeCity {
+			alternatives = append(
+				alternatives,
+				buildSimilarCondition(
+					expressions.citySimilarity, expressions.trigramsCount))
+		}
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// Builds relevance expression of the building. The more lexemes match the
+// query and the more name is similar to the query, the greater relevance is.
+// Arguments of the expression are appended to passed arguments.
+//
+// Relevance is an integer, which is the relevance of the in-memory repository
+// multiplied by ten times the count of the query trigrams, so it is compared
+// exactly.
+func buildRelevanceExpression(
+		search *logic.BuildingSearch, args []any) (string, []any) {
+	expressions, args := buildSearchExpressions(search, args)
+
+	similarity := expressions.nameSimilarity
+	if search.IncludeCity {
+		similarity += " + " + expressions.citySimilarity
+	}
+	expression := fmt.Sprintf(
+		"(%s * %d + 10 * (%s))",
+		expressions.textMatch,
+		expressions.trigramsCount,
+		similarity)
+
+	return expression, args
+}
+
+// Builds keyset pagination condition that selects buildings going after the
+// cursor in relevance order. Relevance of the cursor is computed from its
+// name and city, so it is equal to the relevance of the building itself.
+func buildRelevanceKeysetCondition(
+		search *logic.BuildingSearch,
+		cursor *logic.BuildingCursor,
+		args []any) (string, []any) {
+	relevance, args := buildRelevanceExpression(search, args)
+	relevancePlaceholder := fmt.Sprintf("?%d", len(args) + 1)
+	idPlaceholder := fmt.Sprintf("?%d", len(args) + 2)
+	args = append(
+		args, computeRelevance(cursor.Name, cursor.City, search), cursor.Id)
+
+	// Less relevant buildings or equally relevant buildings with greater
+	// identifiers go after the cursor.
+	condition := fmt.Sprintf(
+		"(%[1]s < %[2]s OR (%[1]s = %[2]s AND id > %[3]s))",
+		relevance,
+		relevancePlaceholder,
+		idPlaceholder)
+
+	return condition, args
+}
+
+// Builds condition that the similarity expression, which counts query
+// trigrams, reaches the threshold.
+func buildSimilarCondition(similarity string, trigramsCount int) string {
+	return fmt.Sprintf(
+		"%s * %d >= %d",
+		similarity,
+		similarityThresholdDenominator,
+		similarityThresholdNumerator * trigramsCount)
+}
+
+// Builds expression of the sum of passed terms, which is 0 without them.
+func buildSumExpression(terms []string) string {
+	if len(terms) == 0 {
+		return "0"
+	}
+	return "(" + strings.Join(terms, " + ") + ")"
+}
+
+// Computes relevance of the building with passed name and city the same way as
+// the relevance expression does.
+func computeRelevance(
+		name string, city string, search *logic.BuildingSearch) int64 {
+	trigrams := getTrigrams(search.Query)
+
+	var relevance int64
+	if matchesText(name, city, search) {
+		relevance += int64(len(trigrams))
+	}
+	similarity := countCommonTrigrams(trigrams, name)
+	if search.IncludeCity {
+		similarity += countCommonTrigrams(trigrams, city)
+	}
+	return relevance + 10 * int64(similarity)
+}
+
+// Checks that all words of the query are prefixes of the name lexemes or the
+// city lexemes if city is included to the search. Query without words matches
+// nothing.
+func matchesText(name string, city string, search *logic.BuildingSearch) bool {
+	lexemes := getLexemes(name)
+	if search.IncludeCity {
+		lexemes = append(lexemes, getLexemes(city)...)
+	}
+
+	words := getLexemes(search.Query)
+	if len(words) == 0 {
+		return false
+	}
+	for _, word := range words {
+		hasPrefix := func(lexeme string) bool {
+			return strings.HasPrefix(lexeme, word)
+		}
+		if !slices.ContainsFunc(lexemes, hasPrefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// Counts passed trigrams found in the text.
+func countCommonTrigrams(trigrams []string, text string) int {
+	textTrigrams := getTrigrams(text)
+	var count int
+	for _, trigram := range trigrams {
+		if _, ok := slices.BinarySearch(textTrigrams, trigram); ok {
+			count++
+		}
+	}
+	return count
+}
+
+// Gets lexemes of the text like "simple" text search configuration does: words
+// of letters and digits in lower case.
+func getLexemes(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Gets sorted distinct trigrams of the text like pg_trgm extension does: each
+// lexeme is padded with two spaces before and one space after it.
+func getTrigrams(text string) []string {
+	var trigrams []string
+	for _, lexeme := range getLexemes(text) {
+		runes := []rune("  " + lexeme + " ")
+		for i := 0; i + 3 <= len(runes); i++ {
+			trigrams = append(trigrams, string(runes[i:i + 3]))
+		}
+	}
+	slices.Sort(trigrams)
+	return slices.Compact(trigrams)
+}
+
+// Joins lexemes of the text to the stored form, where each lexeme follows a
+// space and the last one is followed by a space.
+func joinLexemes(text string) string {
+	return " " + strings.Join(getLexemes(text), " ") + " "
+}
+
+// Joins trigrams of the text to the stored form, where each trigram is placed
+// between separators.
+func joinTrigrams(text string) string {
+	return "|" + strings.Join(getTrigrams(text), "|") + "|"
+}
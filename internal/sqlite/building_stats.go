@@ -0,0 +1,108 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+const (
+	// SQLite has no grouping sets, so statistics are computed by a single
+	// query of grouping selects over the filtered buildings, so all of them are
+	// consistent. The first column tells which grouping a row belongs to.
+	getStatsQueryPrefix = `
+		WITH filtered AS (
+			SELECT city, handover_year, floors_count FROM building
+	`
+
+	getStatsQuerySuffix = `
+		)
+		SELECT 1, city, NULL, COUNT(*), NULL, NULL, NULL
+			FROM filtered GROUP BY city
+		UNION ALL
+		SELECT 2, NULL, handover_year, COUNT(*), NULL, NULL, NULL
+			FROM filtered GROUP BY handover_year
+		UNION ALL
+		SELECT 3, NULL, NULL, COUNT(*), MIN(floors_count), MAX(floors_count),
+				AVG(floors_count)
+			FROM filtered
+		ORDER BY 1, 4 DESC, 2, 3
+	`
+
+	// Values of the first column for each grouping.
+	cityGrouping = 1
+	handoverYearGrouping = 2
+	totalGrouping = 3
+)
+
+// Builds query to get aggregate statistics of the buildings according to
+// filter parameters, ignoring sort keys and pagination.
+func buildGetStatsQuery(
+		filters *logic.BuildingFilters) (query string, args []any) {
+	query = getStatsQueryPrefix
+
+	// Join filter conditions with query prefix.
+	conditions, args := buildFilterConditions(filters, args)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Close a query and return it.
+	query += getStatsQuerySuffix + ";"
+	return query, args
+}
+
+// Scans rows of the statistics query to the statistics structure.
+func scanStats(rows *sql.Rows) (*logic.BuildingStats, error) {
+	var stats logic.BuildingStats
+
+	for rows.Next() {
+		// Try to scan a row of one of the groupings.
+		var (
+			grouping int
+			city *string
+			handoverYear *uint64
+			count uint64
+			minFloorsCount *uint64
+			maxFloorsCount *uint64
+			avgFloorsCount *float64
+		)
+		err := rows.Scan(
+			&grouping,
+			&city,
+			&handoverYear,
+			&count,
+			&minFloorsCount,
+			&maxFloorsCount,
+			&avgFloorsCount)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to scan stats row: %w", translateError(err))
+		}
+
+		// Place row values according to its grouping.
+		switch grouping {
+		case cityGrouping:
+			stats.CityCounts = append(
+				stats.CityCounts, logic.NewValueCount(*city, count))
+		case handoverYearGrouping:
+			stats.HandoverYearCounts = append(
+				stats.HandoverYearCounts, logic.NewValueCount(*handoverYear, count))
+		case totalGrouping:
+			stats.Count = count
+			stats.MinFloorsCount = minFloorsCount
+			stats.MaxFloorsCount = maxFloorsCount
+			stats.AvgFloorsCount = avgFloorsCount
+		}
+	}
+
+	// Check rows error after iterations completion.
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf(
+			"error after rows iteration: %w", translateError(err))
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,38 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	// Pure Go SQLite driver, so binary is built without cgo.
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// Name of the SQLite driver of database/sql, which is registered by the
+	// driver package.
+	driverName = "sqlite"
+
+	// Parameters of the connections. Write-ahead log lets readers work
+	// together with a writer, busy timeout makes concurrent writers wait for
+	// each other and immediate transactions take write lock at the start, so
+	// they do not fail to upgrade read lock.
+	dataSourceParams = "?_pragma=busy_timeout(5000)" +
+		"&_pragma=journal_mode(WAL)" +
+		"&_txlock=immediate"
+)
+
+// Opens database file with passed path, creating it if it does not exist.
+func openDatabase(ctx context.Context, path string) (*sql.DB, error) {
+	// Try to open database and check that file is accessible.
+	db, err := sql.Open(driverName, path + dataSourceParams)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"errors"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+// Extended result codes of the SQLite errors, which are translated to the
+// logic errors.
+const (
+	constraintCheckCode = 275
+	constraintPrimaryKeyCode = 1555
+	constraintUniqueCode = 2067
+)
+
+// Primary result codes of the SQLite errors, which mean that database is
+// temporarily unavailable, because it is used by another connection.
+const (
+	busyCode = 5
+	lockedCode = 6
+)
+
+// Error of the driver, which has a result code. It is matched by the method,
+// so translation does not depend on the error type of the driver.
+type codeError interface {
+	Code() int
+}
+
+// Error of the building which numeric values do not fit integer columns. Such
+// values can not be passed to the driver, so they are rejected before the
+// statements are executed.
+var errNumericValueOutOfRange = logic.NewError(
+		logic.ErrValidation,
+		"numeric_value_out_of_range",
+		"numeric value of the building is out of range")
+
+// Translates passed database error to the logic error of the corresponding
+// kind. Returns passed error as is if it has no corresponding kind.
+func translateError(err error) error {
+	var codeErr codeError
+	if !errors.As(err, &codeErr) {
+		return err
+	}
+
+	switch code := codeErr.Code(); {
+	case code == constraintUniqueCode || code == constraintPrimaryKeyCode:
+		return logic.WrapError(
+			logic.ErrConflict,
+			"unique_violation",
+			"building conflicts with an existing one",
+			err)
+	case code == constraintCheckCode:
+		return logic.WrapError(
+			logic.ErrValidation,
+			"check_violation",
+			"building violates a constraint of the storage",
+			err)
+	case code & 0xff == busyCode || code & 0xff == lockedCode:
+		return logic.WrapError(
+			logic.ErrUnavailable,
+			"database_unavailable",
+			"database is unavailable",
+			err)
+	}
+	return err
+}
+
+// Checks that item error of the batch is caused by the building itself, so it
+// is rejected instead of failing the whole batch.
+func isRejection(err error) bool {
+	return errors.Is(err, logic.ErrValidation) ||
+		errors.Is(err, logic.ErrConflict)
+}
@@ -0,0 +1,182 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rylenko/leadgen-market-task/internal/logic"
+)
+
+const (
+	completeIdempotencyKeyStatement = `
+		UPDATE idempotency_key
+			SET status = ?2, content_type = ?3, body = ?4
+			WHERE key = ?1;
+	`
+
+	getIdempotencyKeyQuery = `
+		SELECT request_hash, status, content_type, body FROM idempotency_key
+			WHERE key = ?1;
+	`
+
+	releaseIdempotencyKeyStatement = `
+		DELETE FROM idempotency_key WHERE key = ?1 AND status IS NULL;
+	`
+
+	reserveIdempotencyKeyQuery = `
+		INSERT INTO idempotency_key (key, request_hash, created_at)
+			VALUES (?1, ?2, ?5)
+			ON CONFLICT (key) DO UPDATE SET
+				request_hash = excluded.request_hash,
+				status = NULL,
+				content_type = NULL,
+				body = NULL,
+				created_at = excluded.created_at
+			WHERE idempotency_key.created_at < ?3
+				OR (idempotency_key.status IS NULL
+					AND idempotency_key.created_at < ?4)
+			RETURNING key;
+	`
+)
+
+// IdempotencyRepositoryImpl is a SQLite implementation of idempotency key
+// repository.
+type IdempotencyRepositoryImpl struct {
+	db *sql.DB
+}
+
+// Closes opened repository implementation.
+func (repository *IdempotencyRepositoryImpl) Close() {
+	repository.db.Close()
+}
+
+// Complete stores response of the request with passed key.
+func (repository *IdempotencyRepositoryImpl) Complete(
+		ctx context.Context,
+		key string,
+		response *logic.IdempotentResponse) error {
+	_, err := repository.db.ExecContext(
+		ctx,
+		completeIdempotencyKeyStatement,
+		key,
+		response.Status,
+		response.ContentType,
+		response.Body)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to complete idempotency key %q: %w",
+			key,
+			translateError(err))
+	}
+	return nil
+}
+
+// Init creates the database schema if it does not exist.
+func (repository *IdempotencyRepositoryImpl) Init(ctx context.Context) error {
+	_, err := repository.db.ExecContext(
+		ctx, createIdempotencyKeySchemaStatement)
+	if err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+	return nil
+}
+
+// Release deletes reservation of passed key if the request is not completed.
+func (repository *IdempotencyRepositoryImpl) Release(
+		ctx context.Context, key string) error {
+	_, err := repository.db.ExecContext(
+		ctx, releaseIdempotencyKeyStatement, key)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to release idempotency key %q: %w",
+			key,
+			translateError(err))
+	}
+	return nil
+}
+
+// Reserve inserts passed key or replaces expired or abandoned one and returns
+// nil. Otherwise, returns the existing record of the key. If the existing
+// reservation is released before its record is got, reserving is retried.
+func (repository *IdempotencyRepositoryImpl) Reserve(
+		ctx context.Context,
+		key string,
+		requestHash string,
+		expiredBefore time.Time,
+		abandonedBefore time.Time) (*logic.IdempotencyRecord, error) {
+	for {
+		// Try to reserve the key. Times are compared as Unix time in
+		// nanoseconds.
+		var reserved string
+		err := repository.db.QueryRowContext(
+			ctx,
+			reserveIdempotencyKeyQuery,
+			key,
+			requestHash,
+			expiredBefore.UnixNano(),
+			abandonedBefore.UnixNano(),
+			time.Now().UnixNano()).Scan(&reserved)
+		if err == nil {
+			return nil, nil
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf(
+				"failed to reserve idempotency key %q: %w",
+				key,
+				translateError(err))
+		}
+
+		// Key is used by another request, so try to get its record.
+		record, err := repository.get(ctx, key)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get idempotency key %q: %w", key, translateError(err))
+		}
+		return record, nil
+	}
+}
+
+// Gets the record of passed key.
+func (repository *IdempotencyRepositoryImpl) get(
+		ctx context.Context, key string) (*logic.IdempotencyRecord, error) {
+	var (
+		record logic.IdempotencyRecord
+		status *int
+		contentType *string
+		body []byte
+	)
+	err := repository.db.QueryRowContext(ctx, getIdempotencyKeyQuery, key).Scan(
+		&record.RequestHash, &status, &contentType, &body)
+	if err != nil {
+		return nil, err
+	}
+	if status != nil {
+		var contentTypeValue string
+		if contentType != nil {
+			contentTypeValue = *contentType
+		}
+		record.Response = logic.NewIdempotentResponse(
+			*status, contentTypeValue, body)
+	}
+
+	return &record, nil
+}
+
+// Opens a new connection to idempotency key repository stored in the database
+// file with passed path.
+func OpenIdempotencyRepositoryImpl(
+		ctx context.Context, path string) (*IdempotencyRepositoryImpl, error) {
+	// Try to open the database file.
+	db, err := openDatabase(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new database wrapper instance.
+	impl := &IdempotencyRepositoryImpl{db: db}
+	return impl, nil
+}
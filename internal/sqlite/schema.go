@@ -0,0 +1,60 @@
+package sqlite
+
+// Schema of the building table. Statements are idempotent, so they are
+// executed on each initialization instead of versioned migrations.
+//
+// SQLite has neither text search nor trigram extensions, so lexemes and
+// trigrams of the name and the city are computed by the repository on each
+// write and stored next to them. Lowercase values are stored for the same
+// reason, because lower() of SQLite changes only ASCII letters. They have
+// NOCASE collation, so their indexes serve LIKE prefix matching.
+const createBuildingSchemaStatement = `
+	CREATE TABLE IF NOT EXISTS building (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		city TEXT NOT NULL,
+		handover_year INTEGER NOT NULL
+			CHECK (handover_year BETWEEN 0 AND 2147483647),
+		floors_count INTEGER NOT NULL
+			CHECK (floors_count BETWEEN 0 AND 2147483647),
+		external_source TEXT,
+		external_id TEXT,
+		lower_name TEXT NOT NULL COLLATE NOCASE,
+		lower_city TEXT NOT NULL COLLATE NOCASE,
+		name_lexemes TEXT NOT NULL,
+		city_lexemes TEXT NOT NULL,
+		name_trigrams TEXT NOT NULL,
+		city_trigrams TEXT NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS building_city_index
+		ON building (city);
+
+	CREATE INDEX IF NOT EXISTS building_handover_year_index
+		ON building (handover_year);
+
+	CREATE INDEX IF NOT EXISTS building_floors_count_index
+		ON building (floors_count);
+
+	CREATE INDEX IF NOT EXISTS building_name_prefix_index
+		ON building (lower_name);
+
+	CREATE INDEX IF NOT EXISTS building_city_prefix_index
+		ON building (lower_city);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS building_external_id_index
+		ON building (external_source, external_id);
+`
+
+// Schema of the idempotency key table. Creation time is stored as Unix time
+// in nanoseconds, so it is compared as a number.
+const createIdempotencyKeySchemaStatement = `
+	CREATE TABLE IF NOT EXISTS idempotency_key (
+		key TEXT PRIMARY KEY,
+		request_hash TEXT NOT NULL,
+		status INTEGER,
+		content_type TEXT,
+		body BLOB,
+		created_at INTEGER NOT NULL
+	);
+`